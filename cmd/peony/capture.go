@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// maxPipedContent bounds how much piped input is taken as one thought.
+const maxPipedContent = 1 << 20
+
+// stdinIsPiped reports whether stdin is a pipe or file rather than a terminal.
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// readPipedContent reads all of r as the content of a new thought.
+func readPipedContent(r io.Reader) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxPipedContent+1))
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	if len(data) > maxPipedContent {
+		return "", fmt.Errorf("read stdin: input is larger than %d bytes", maxPipedContent)
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("read stdin: input is not UTF-8 text")
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	return trimBlankLines(strings.Split(text, "\n")), nil
+}
+
+// captureInEditor lets a new thought be written in the editor, starting from content and feeling.
+// It returns nil when the editor is left empty, which cancels the capture.
+func captureInEditor(reader *bufio.Reader, content string, feeling core.Feeling) (*tendDraft, error) {
+	text := renderCaptureTemplate(content, feeling)
+	for {
+		var err error
+		text, err = editText(text)
+		if err != nil {
+			return nil, fmt.Errorf("edit: %w", err)
+		}
+
+		draft, err := parseCaptureText(text)
+		if errors.Is(err, errEmptyContent) {
+			return nil, nil
+		}
+		if err == nil {
+			return draft, nil
+		}
+
+		fmt.Fprintf(os.Stderr, "The thought could not be read:\n%s\n", indentLines(err.Error(), "  "))
+		again, promptErr := promptYesNo(reader, "Open the editor again to fix it?")
+		if promptErr != nil || !again {
+			return nil, fmt.Errorf("edit: %w", err)
+		}
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/divijg19/peony/internal/config"
 	"github.com/divijg19/peony/internal/core"
@@ -24,6 +23,9 @@ func loadRuntimeConfig() (config.Config, error) {
 	runtimeConfigOnce.Do(func() {
 		runtimeConfig, runtimeConfigErr = config.Load()
 		core.SettleDuration = config.SettleDuration(runtimeConfig)
+		core.ResurfacePolicy = config.SpacingPolicy(runtimeConfig)
+		core.Windows = config.Windows(runtimeConfig)
+		core.DailyBudget = config.DailyBudget(runtimeConfig)
 	})
 	return runtimeConfig, runtimeConfigErr
 }
@@ -41,7 +43,30 @@ func printConfig(cfg config.Config) int {
 	} else {
 		fmt.Printf("Editor: %s\n", cfg.Editor)
 	}
-	fmt.Printf("SettleDuration: %s\n", config.SettleDuration(cfg))
+	fmt.Printf("SettleDuration: %s\n", core.FormatSpan(config.SettleDuration(cfg)))
+	policy := config.SpacingPolicy(cfg)
+	fmt.Printf("Spacing: %s\n", policy.Spacing)
+	fmt.Printf("SpacingCap: %s\n", core.FormatSpan(policy.Cap))
+	printUnset := func(name, value string) {
+		if value == "" {
+			fmt.Printf("%s: (unset)\n", name)
+		} else {
+			fmt.Printf("%s: %s\n", name, value)
+		}
+	}
+	printUnset("SurfaceHours", cfg.SurfaceHours)
+	printUnset("QuietHours", cfg.QuietHours)
+	printUnset("QuietDays", cfg.QuietDays)
+	if budget := config.DailyBudget(cfg); budget > 0 {
+		fmt.Printf("DailyBudget: %d\n", budget)
+	} else {
+		fmt.Println("DailyBudget: (unlimited)")
+	}
+	if cfg.AddWithEditor {
+		fmt.Println("AddWithEditor: on")
+	} else {
+		fmt.Println("AddWithEditor: off")
+	}
 	return 0
 }
 
@@ -83,7 +108,7 @@ func configureEditor(cfg config.Config) (config.Config, int) {
 // configureSettleDuration prompts for and sets the settle duration.
 func configureSettleDuration(cfg config.Config, durationValue string) (config.Config, int) {
 	if strings.TrimSpace(durationValue) == "" {
-		fmt.Print("Settle duration (e.g. 18h, 2h30m): ")
+		fmt.Print("Settle duration (e.g. 18h, 3d, 2w, 1w2d): ")
 		reader := bufio.NewReader(os.Stdin)
 		line, err := reader.ReadString('\n')
 		if err != nil {
@@ -93,17 +118,165 @@ func configureSettleDuration(cfg config.Config, durationValue string) (config.Co
 		durationValue = strings.TrimSpace(line)
 	}
 
-	dur, err := time.ParseDuration(strings.TrimSpace(durationValue))
+	dur, err := core.ParseSpan(durationValue)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "config: invalid settle duration")
+		fmt.Fprintf(os.Stderr, "config: invalid settle duration: %v\n", err)
 		return cfg, 2
 	}
 
-	cfg.SettleDuration = dur.String()
+	cfg.SettleDuration = core.FormatSpan(dur)
 	core.SettleDuration = dur
 	return cfg, 0
 }
 
+// configureSpacing prompts for and sets the resurfacing spacing policy.
+func configureSpacing(cfg config.Config, spacingValue string) (config.Config, int) {
+	if strings.TrimSpace(spacingValue) == "" {
+		fmt.Print("Spacing (fixed, linear, exponential): ")
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config: read: %v\n", err)
+			return cfg, 1
+		}
+		spacingValue = strings.TrimSpace(line)
+	}
+
+	spacing, err := core.ParseSpacing(spacingValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		return cfg, 2
+	}
+
+	cfg.Spacing = string(spacing)
+	core.ResurfacePolicy = config.SpacingPolicy(cfg)
+	return cfg, 0
+}
+
+// configureSpacingCap prompts for and sets the longest interval a growing spacing policy may choose.
+func configureSpacingCap(cfg config.Config, capValue string) (config.Config, int) {
+	if strings.TrimSpace(capValue) == "" {
+		fmt.Print("Spacing cap (e.g. 2w, 1mo): ")
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config: read: %v\n", err)
+			return cfg, 1
+		}
+		capValue = strings.TrimSpace(line)
+	}
+
+	limit, err := core.ParseSpan(capValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: invalid spacing cap: %v\n", err)
+		return cfg, 2
+	}
+
+	cfg.SpacingCap = core.FormatSpan(limit)
+	core.ResurfacePolicy = config.SpacingPolicy(cfg)
+	return cfg, 0
+}
+
+// configureHours prompts for and sets either the reflection windows or the quiet windows.
+func configureHours(cfg config.Config, setting string, hoursValue string) (config.Config, int) {
+	if strings.TrimSpace(hoursValue) == "" {
+		fmt.Printf("%s (e.g. 18:00-22:00, or none): ", setting)
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config: read: %v\n", err)
+			return cfg, 1
+		}
+		hoursValue = strings.TrimSpace(line)
+	}
+
+	windows, err := core.ParseWindows(hoursValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		return cfg, 2
+	}
+
+	switch setting {
+	case "surfaceHours":
+		cfg.SurfaceHours = core.FormatWindows(windows)
+	case "quietHours":
+		cfg.QuietHours = core.FormatWindows(windows)
+	}
+	core.Windows = config.Windows(cfg)
+	return cfg, 0
+}
+
+// configureQuietDays prompts for and sets the weekdays on which nothing surfaces.
+func configureQuietDays(cfg config.Config, daysValue string) (config.Config, int) {
+	if strings.TrimSpace(daysValue) == "" {
+		fmt.Print("Quiet days (e.g. weekdays, sat,sun, or none): ")
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config: read: %v\n", err)
+			return cfg, 1
+		}
+		daysValue = strings.TrimSpace(line)
+	}
+
+	days, err := core.ParseWeekdays(daysValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		return cfg, 2
+	}
+
+	cfg.QuietDays = core.FormatWeekdays(days)
+	core.Windows = config.Windows(cfg)
+	return cfg, 0
+}
+
+// configureDailyBudget prompts for and sets how many ripe thoughts may be offered per day.
+func configureDailyBudget(cfg config.Config, budgetValue string) (config.Config, int) {
+	if strings.TrimSpace(budgetValue) == "" {
+		fmt.Print("Daily budget (e.g. 3, or none): ")
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config: read: %v\n", err)
+			return cfg, 1
+		}
+		budgetValue = strings.TrimSpace(line)
+	}
+
+	budget, err := config.ParseDailyBudget(budgetValue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		return cfg, 2
+	}
+
+	cfg.DailyBudget = budget
+	core.DailyBudget = budget
+	return cfg, 0
+}
+
+// configureAddWithEditor sets whether `peony add` opens the editor by default.
+func configureAddWithEditor(cfg config.Config, value string) (config.Config, int) {
+	if strings.TrimSpace(value) == "" {
+		fmt.Print("Open the editor when adding a thought? (on/off): ")
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "config: read: %v\n", err)
+			return cfg, 1
+		}
+		value = strings.TrimSpace(line)
+	}
+
+	on, err := config.ParseSwitch(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config: %v\n", err)
+		return cfg, 2
+	}
+
+	cfg.AddWithEditor = on
+	return cfg, 0
+}
+
 // cmdConfigure handles `peony config`.
 func cmdConfigure(args []string) int {
 	cfg, cfgErr := loadRuntimeConfig()
@@ -119,6 +292,18 @@ func cmdConfigure(args []string) int {
 		setEditor       bool
 		setSettle       bool
 		settleValue     string
+		setSpacing      bool
+		spacingValue    string
+		setSpacingCap   bool
+		spacingCapValue string
+		hoursSettings   []string
+		hoursValues     []string
+		setQuietDays    bool
+		quietDaysValue  string
+		setBudget       bool
+		budgetValue     string
+		setAddEditor    bool
+		addEditorValue  string
 		unrecognizedArg string
 	)
 
@@ -133,6 +318,44 @@ func cmdConfigure(args []string) int {
 				settleValue = args[i+1]
 				i++
 			}
+		case "--spacing", "spacing":
+			setSpacing = true
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				spacingValue = args[i+1]
+				i++
+			}
+		case "--spacingCap", "spacingCap":
+			setSpacingCap = true
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				spacingCapValue = args[i+1]
+				i++
+			}
+		case "--surfaceHours", "surfaceHours", "--quietHours", "quietHours":
+			value := ""
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				value = args[i+1]
+				i++
+			}
+			hoursSettings = append(hoursSettings, strings.TrimPrefix(arg, "--"))
+			hoursValues = append(hoursValues, value)
+		case "--quietDays", "quietDays":
+			setQuietDays = true
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				quietDaysValue = args[i+1]
+				i++
+			}
+		case "--dailyBudget", "dailyBudget":
+			setBudget = true
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				budgetValue = args[i+1]
+				i++
+			}
+		case "--addWithEditor", "addWithEditor":
+			setAddEditor = true
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				addEditorValue = args[i+1]
+				i++
+			}
 		default:
 			unrecognizedArg = arg
 		}
@@ -159,6 +382,54 @@ func cmdConfigure(args []string) int {
 		}
 	}
 
+	if setSpacing {
+		var code int
+		cfg, code = configureSpacing(cfg, spacingValue)
+		if code != 0 {
+			return code
+		}
+	}
+
+	if setSpacingCap {
+		var code int
+		cfg, code = configureSpacingCap(cfg, spacingCapValue)
+		if code != 0 {
+			return code
+		}
+	}
+
+	for idx, setting := range hoursSettings {
+		var code int
+		cfg, code = configureHours(cfg, setting, hoursValues[idx])
+		if code != 0 {
+			return code
+		}
+	}
+
+	if setQuietDays {
+		var code int
+		cfg, code = configureQuietDays(cfg, quietDaysValue)
+		if code != 0 {
+			return code
+		}
+	}
+
+	if setBudget {
+		var code int
+		cfg, code = configureDailyBudget(cfg, budgetValue)
+		if code != 0 {
+			return code
+		}
+	}
+
+	if setAddEditor {
+		var code int
+		cfg, code = configureAddWithEditor(cfg, addEditorValue)
+		if code != 0 {
+			return code
+		}
+	}
+
 	if err := config.Save(cfg); err != nil {
 		fmt.Fprintf(os.Stderr, "config: %v\n", err)
 		return 1
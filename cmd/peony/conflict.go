@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/divijg19/peony/internal/core"
+	"github.com/divijg19/peony/internal/storage"
+)
+
+// Conflict markers used when both versions are handed to the editor to merge by hand.
+const (
+	conflictMine   = "<<<<<<< yours"
+	conflictBase   = "||||||| when you started"
+	conflictSplit  = "======="
+	conflictTheirs = ">>>>>>> saved elsewhere"
+)
+
+// resolveEditConflict is called when a thought was saved elsewhere while it was being edited.
+// base is the draft as it was when editing began and baseState the thought's state then, mine the
+// edited draft and conflict the newer save. It returns the draft to save instead, or nil when the
+// user cancels.
+func resolveEditConflict(reader *bufio.Reader, guard *interruptGuard, base tendDraft, baseState core.State, mine tendDraft, conflict *storage.ConflictError) (*tendDraft, error) {
+	theirs := conflict.Current
+	theirsFeeling := core.Feeling{Valence: theirs.Valence, Energy: theirs.Energy}
+
+	// A thought released or archived elsewhere should not quietly take an edit meant for the
+	// thought as it was, so a state change is always pointed out.
+	stateMoved := theirs.CurrentState != baseState
+	if stateMoved {
+		fmt.Printf("\n%s while you were editing: it is now %s (it was %s).\n", conflict.Error(), theirs.CurrentState, baseState)
+	}
+
+	// Only the feeling, tags or state moved elsewhere: nothing to merge by hand. Keep their
+	// feeling and tags unless this edit changed them too.
+	if theirs.Content == base.Content {
+		resolved := mine
+		if mine.Feeling.Equal(base.Feeling) {
+			resolved.Feeling = theirsFeeling
+		}
+		if mine.Tags == nil || slices.Equal(mine.Tags, base.Tags) {
+			resolved.Tags = theirs.Tags
+		}
+		if !stateMoved {
+			return &resolved, nil
+		}
+		keep, err := promptYesNo(reader, "Save your edit anyway?")
+		if err != nil {
+			return nil, err
+		}
+		if !keep {
+			return nil, nil
+		}
+		return &resolved, nil
+	}
+
+	if !stateMoved {
+		fmt.Printf("\n%s while you were editing.\n", conflict.Error())
+	}
+	fmt.Println("\nSaved elsewhere, compared with when you started:")
+	fmt.Println(indentLines(renderWordDiff(core.DiffWords(base.Content, theirs.Content)), "  "))
+	fmt.Println("\nYours, compared with when you started:")
+	fmt.Println(indentLines(renderWordDiff(core.DiffWords(base.Content, mine.Content)), "  "))
+	fmt.Println()
+
+	for {
+		choice, err := promptChoice(reader, "Which version should be kept?", []string{"mine", "theirs", "merge", "cancel"})
+		if err != nil {
+			return nil, err
+		}
+
+		switch choice {
+		case "mine":
+			return &mine, nil
+		case "theirs":
+			resolved := mine
+			resolved.Content = theirs.Content
+			resolved.Feeling = theirsFeeling
+			resolved.Tags = theirs.Tags
+			return &resolved, nil
+		case "cancel":
+			return nil, nil
+		}
+
+		draft := mine
+		draft.Content = strings.Join([]string{
+			conflictMine, mine.Content,
+			conflictBase, base.Content,
+			conflictSplit, theirs.Content,
+			conflictTheirs,
+		}, "\n")
+
+		var merged *tendDraft
+		err = guard.run(interruptIgnore, func() error {
+			var err error
+			merged, err = OpenEditorWithTemplate(draft)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("merge: %w", err)
+		}
+		if merged == nil {
+			return nil, fmt.Errorf("merge: no draft returned")
+		}
+		if hasConflictMarkers(merged.Content) {
+			fmt.Fprintln(os.Stderr, "The merge still has conflict markers; please choose again.")
+			continue
+		}
+		return merged, nil
+	}
+}
+
+// hasConflictMarkers reports whether any line of s is still a conflict marker.
+func hasConflictMarkers(s string) bool {
+	for _, line := range strings.Split(s, "\n") {
+		switch strings.TrimRight(line, " \t\r") {
+		case conflictMine, conflictBase, conflictSplit, conflictTheirs:
+			return true
+		}
+	}
+	return false
+}
+
+// indentLines prefixes every line of s with prefix.
+func indentLines(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+	"github.com/divijg19/peony/internal/storage"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestResolveEditConflictWithoutContentChange(t *testing.T) {
+	base := tendDraft{Content: "a thought", Feeling: core.Feeling{Valence: intPtr(1)}, Tags: []string{"cabin"}}
+	theirsSaved := core.Thought{
+		ID:           1,
+		Content:      "a thought",
+		CurrentState: core.StateCaptured,
+		Valence:      intPtr(-1),
+		Tags:         []string{"cabin", "work"},
+		UpdatedAt:    time.Now(),
+	}
+
+	tests := []struct {
+		name      string
+		mine      tendDraft
+		theirs    core.State
+		input     string
+		wantNil   bool
+		wantTags  []string
+		wantValen int
+	}{
+		{
+			name:      "feeling and tags moved elsewhere are kept",
+			mine:      tendDraft{Content: "a thought, edited", Feeling: base.Feeling, Tags: base.Tags},
+			theirs:    core.StateCaptured,
+			wantTags:  []string{"cabin", "work"},
+			wantValen: -1,
+		},
+		{
+			name:      "my own feeling and tags win",
+			mine:      tendDraft{Content: "a thought, edited", Feeling: core.Feeling{Valence: intPtr(2)}, Tags: []string{"ideas"}},
+			theirs:    core.StateCaptured,
+			wantTags:  []string{"ideas"},
+			wantValen: 2,
+		},
+		{
+			name:      "a state change asks first",
+			mine:      tendDraft{Content: "a thought, edited", Feeling: base.Feeling, Tags: base.Tags},
+			theirs:    core.StateReleased,
+			input:     "y\n",
+			wantTags:  []string{"cabin", "work"},
+			wantValen: -1,
+		},
+		{
+			name:    "a state change can be declined",
+			mine:    tendDraft{Content: "a thought, edited", Feeling: base.Feeling, Tags: base.Tags},
+			theirs:  core.StateArchived,
+			input:   "n\n",
+			wantNil: true,
+		},
+	}
+	for _, tt := range tests {
+		current := theirsSaved
+		current.CurrentState = tt.theirs
+		conflict := &storage.ConflictError{ThoughtID: 1, Current: current}
+		reader := bufio.NewReader(strings.NewReader(tt.input))
+
+		got, err := resolveEditConflict(reader, nil, base, core.StateCaptured, tt.mine, conflict)
+		if err != nil {
+			t.Errorf("%s: error = %v", tt.name, err)
+			continue
+		}
+		if tt.wantNil {
+			if got != nil {
+				t.Errorf("%s: resolved = %+v, want nothing saved", tt.name, got)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("%s: resolved nothing", tt.name)
+			continue
+		}
+		if got.Content != tt.mine.Content {
+			t.Errorf("%s: content = %q, want %q", tt.name, got.Content, tt.mine.Content)
+		}
+		if !slices.Equal(got.Tags, tt.wantTags) {
+			t.Errorf("%s: tags = %v, want %v", tt.name, got.Tags, tt.wantTags)
+		}
+		if got.Feeling.Valence == nil || *got.Feeling.Valence != tt.wantValen {
+			t.Errorf("%s: valence = %v, want %d", tt.name, got.Feeling.Valence, tt.wantValen)
+		}
+		if rest, _ := reader.ReadString('\n'); rest != "" {
+			t.Errorf("%s: left %q unread", tt.name, rest)
+		}
+	}
+}
+
+func TestResolveEditConflictKeepsTheirs(t *testing.T) {
+	base := tendDraft{Content: "a thought"}
+	mine := tendDraft{Content: "my version"}
+	conflict := &storage.ConflictError{ThoughtID: 1, Current: core.Thought{
+		ID:           1,
+		Content:      "their version",
+		CurrentState: core.StateCaptured,
+		Tags:         []string{"work"},
+		UpdatedAt:    time.Now(),
+	}}
+
+	got, err := resolveEditConflict(bufio.NewReader(strings.NewReader("theirs\n")), nil, base, core.StateCaptured, mine, conflict)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if got == nil || got.Content != "their version" || !slices.Equal(got.Tags, []string{"work"}) {
+		t.Errorf("resolved = %+v, want their version", got)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/divijg19/peony/internal/storage"
+)
+
+// cmdDB runs database maintenance subcommands.
+func cmdDB(args []string) int {
+	if len(args) != 1 || args[0] != "status" {
+		fmt.Fprintln(os.Stderr, "db: usage: `peony db status`")
+		return 2
+	}
+
+	dbPath, err := storage.ResolveDBPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "db: resolve db path: %v\n", err)
+		return 1
+	}
+
+	// Inspect rather than open, so looking never migrates or creates anything.
+	status, err := storage.Inspect(dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "db: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Database: %s\n", status.Path)
+	switch {
+	case !status.Exists:
+		fmt.Printf("Schema:   none yet; it is created at version %d the first time peony runs\n", status.Latest)
+		return 0
+	case status.TooNew():
+		fmt.Printf("Schema:   version %d, newer than this peony knows (%d); please upgrade peony\n", status.Current, status.Latest)
+	case len(status.Pending) > 0:
+		fmt.Printf("Schema:   version %d of %d; %d step(s) run the next time peony opens it\n", status.Current, status.Latest, len(status.Pending))
+	default:
+		fmt.Printf("Schema:   version %d (up to date)\n", status.Current)
+	}
+
+	if len(status.Applied) > 0 {
+		fmt.Println("\nApplied:")
+		for _, step := range status.Applied {
+			applied := "before steps were recorded"
+			if step.AppliedAt != nil {
+				applied = step.AppliedAt.Local().Format("2006-01-02 15:04")
+			}
+			name := step.Name
+			if name == "" {
+				name = "(unknown step)"
+			}
+			fmt.Printf("  v%-3d %-45s %s\n", step.Version, name, applied)
+		}
+	}
+
+	if len(status.Pending) > 0 {
+		fmt.Println("\nPending:")
+		for _, step := range status.Pending {
+			note := ""
+			if step.Risky {
+				note = "backup taken first"
+			}
+			fmt.Printf("  v%-3d %-45s %s\n", step.Version, step.Name, note)
+		}
+	}
+
+	if len(status.Backups) > 0 {
+		fmt.Println("\nBackups:")
+		for _, path := range status.Backups {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+	return 0
+}
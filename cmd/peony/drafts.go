@@ -0,0 +1,355 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+	"github.com/divijg19/peony/internal/storage"
+)
+
+// savedDraft is tend editor text kept because the tend it came from was never saved.
+// Drafts are keyed by the thought's UID, which stays valid even after the thought is purged.
+type savedDraft struct {
+	UID     string
+	SavedAt time.Time
+	Text    string
+	// Base is the thought as it was when the edit began; nil for drafts kept by an older peony.
+	Base *draftBase
+}
+
+// draftBase records what a draft was written against, so that saving it later can tell whether
+// the thought changed in the meantime. It is kept next to the draft as <uid>.base.json.
+type draftBase struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Content   string    `json:"content"`
+	State     string    `json:"state,omitempty"`
+	Valence   *int      `json:"valence,omitempty"`
+	Energy    *int      `json:"energy,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+}
+
+// newDraftBase records base, the draft the editor opened with, and the updated_at and state the
+// thought was read at.
+func newDraftBase(base tendDraft, updatedAt time.Time, state core.State) draftBase {
+	return draftBase{
+		UpdatedAt: updatedAt,
+		Content:   base.Content,
+		State:     string(state),
+		Valence:   base.Feeling.Valence,
+		Energy:    base.Feeling.Energy,
+		Tags:      base.Tags,
+	}
+}
+
+// basis returns the draft the edit started from, and the updated_at and state to expect when
+// saving it. A draft without a recorded base is only trusted while the thought is unchanged since
+// it was kept; otherwise nothing is known about where it started, so saving it always asks first.
+func (d savedDraft) basis(current core.Thought) (tendDraft, time.Time, core.State) {
+	if d.Base != nil {
+		state := core.State(d.Base.State)
+		if state == "" {
+			state = current.CurrentState
+		}
+		return tendDraft{
+			Content: d.Base.Content,
+			Feeling: core.Feeling{Valence: d.Base.Valence, Energy: d.Base.Energy},
+			Tags:    d.Base.Tags,
+		}, d.Base.UpdatedAt, state
+	}
+	if current.UpdatedAt.After(d.SavedAt) {
+		return tendDraft{}, d.SavedAt, current.CurrentState
+	}
+	return tendDraft{
+		Content: current.Content,
+		Feeling: core.Feeling{Valence: current.Valence, Energy: current.Energy},
+		Tags:    current.Tags,
+	}, current.UpdatedAt, current.CurrentState
+}
+
+// draftsDir returns where drafts are kept: a drafts directory next to the database.
+func draftsDir() (string, error) {
+	dbPath, err := storage.ResolveDBPath()
+	if err != nil {
+		return "", fmt.Errorf("resolve db path: %w", err)
+	}
+	return filepath.Join(filepath.Dir(dbPath), "drafts"), nil
+}
+
+func draftPath(uid string) (string, error) {
+	if uid == "" || uid != filepath.Base(uid) || strings.HasPrefix(uid, ".") {
+		return "", fmt.Errorf("invalid thought UID %q", uid)
+	}
+	dir, err := draftsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, uid+".txt"), nil
+}
+
+// draftBasePath returns where the base of the draft at path is kept.
+func draftBasePath(path string) string {
+	return strings.TrimSuffix(path, ".txt") + ".base.json"
+}
+
+// saveDraft keeps text, written against base, as the draft for the thought with uid, replacing
+// any earlier one.
+func saveDraft(uid, text string, base draftBase) error {
+	path, err := draftPath(uid)
+	if err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("save draft: create dir: %w", err)
+	}
+	data, err := json.Marshal(base)
+	if err != nil {
+		return fmt.Errorf("save draft: marshal base: %w", err)
+	}
+	if err := os.WriteFile(draftBasePath(path), data, 0o600); err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+		return fmt.Errorf("save draft: %w", err)
+	}
+	return nil
+}
+
+// loadDraft returns the draft left for the thought with uid, or nil when there is none.
+func loadDraft(uid string) (*savedDraft, error) {
+	path, err := draftPath(uid)
+	if err != nil {
+		return nil, fmt.Errorf("load draft: %w", err)
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load draft: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load draft: %w", err)
+	}
+	draft := &savedDraft{UID: uid, SavedAt: info.ModTime(), Text: string(data)}
+
+	data, err = os.ReadFile(draftBasePath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return draft, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load draft: %w", err)
+	}
+	var base draftBase
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, fmt.Errorf("load draft: parse base: %w", err)
+	}
+	draft.Base = &base
+	return draft, nil
+}
+
+// discardDraft removes the draft for the thought with uid, if there is one.
+func discardDraft(uid string) error {
+	path, err := draftPath(uid)
+	if err != nil {
+		return fmt.Errorf("discard draft: %w", err)
+	}
+	for _, p := range []string{path, draftBasePath(path)} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("discard draft: %w", err)
+		}
+	}
+	return nil
+}
+
+// listDrafts returns every kept draft, most recent first.
+func listDrafts() ([]savedDraft, error) {
+	dir, err := draftsDir()
+	if err != nil {
+		return nil, fmt.Errorf("list drafts: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("list drafts: %w", err)
+	}
+
+	drafts := make([]savedDraft, 0, len(paths))
+	for _, path := range paths {
+		draft, err := loadDraft(strings.TrimSuffix(filepath.Base(path), ".txt"))
+		if err != nil {
+			return nil, fmt.Errorf("list drafts: %w", err)
+		}
+		if draft != nil {
+			drafts = append(drafts, *draft)
+		}
+	}
+	sort.Slice(drafts, func(i, j int) bool { return drafts[i].SavedAt.After(drafts[j].SavedAt) })
+	return drafts, nil
+}
+
+// offerDraft asks whether to pick up the draft left for thought. It returns the draft to restore,
+// or nil to start afresh; declining discards the old draft.
+func offerDraft(reader *bufio.Reader, thought core.Thought) (*savedDraft, error) {
+	draft, err := loadDraft(thought.UID)
+	if err != nil || draft == nil {
+		return nil, err
+	}
+
+	restore, err := promptYesNo(reader, fmt.Sprintf("An unsaved edit of #%d from %s was kept. Restore it?", thought.ID, draft.SavedAt.Format("2006-01-02 15:04")))
+	if err != nil {
+		return nil, err
+	}
+	if restore {
+		return draft, nil
+	}
+	return nil, discardDraft(thought.UID)
+}
+
+// draftOverview summarises a draft by the content it holds, even when the draft does not parse.
+func draftOverview(text string) string {
+	if parsed, err := parseTendText(text); err == nil {
+		return revisionOverview(parsed.Content)
+	}
+	inContent, fences := false, 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == frontMatterFence && fences < 2:
+			fences++
+			inContent = fences == 2
+		case line == contentHeader:
+			inContent = true
+		case line == noteHeader || line == feelingHeader:
+			inContent = false
+		case inContent && line != "" && (fences == 2 || !strings.HasPrefix(line, "//")):
+			return revisionOverview(strings.TrimPrefix(line, bodyEscape))
+		}
+	}
+	return "(content left empty)"
+}
+
+// cmdDrafts lists kept drafts, resumes one in the tend flow, or discards one.
+func cmdDrafts(args []string) int {
+	switch {
+	case len(args) == 0:
+	case len(args) == 1:
+		return resumeDraft(args[0])
+	case len(args) == 2 && args[0] == "discard":
+		return discardDraftFor(args[1])
+	default:
+		fmt.Fprintln(os.Stderr, "drafts: usage: `peony drafts [id | discard <id>]`")
+		return 2
+	}
+
+	drafts, err := listDrafts()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+	if len(drafts) == 0 {
+		fmt.Println("No drafts are waiting.")
+		return 0
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("%-6s %-9s %-18s %s\n", "ID", "UID", "SAVED", "OVERVIEW")
+	for _, draft := range drafts {
+		ref := "gone"
+		if id, err := st.ResolveThoughtRef(draft.UID); err == nil {
+			ref = fmt.Sprintf("%d", id)
+		}
+		fmt.Printf("%-6s %-9s %-18s %s\n", ref, draft.UID, draft.SavedAt.Format("2006-01-02 15:04"), draftOverview(draft.Text))
+	}
+	fmt.Println("\nResume one with `peony drafts <id>`, or drop it with `peony drafts discard <id>`.")
+	return 0
+}
+
+// resumeDraft reopens the tend flow for a thought with its kept draft.
+func resumeDraft(ref string) int {
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+
+	id, err := st.ResolveThoughtRef(ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 2
+	}
+	thought, _, err := st.GetTendThought(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+	draft, err := loadDraft(thought.UID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+	if draft == nil {
+		fmt.Fprintf(os.Stderr, "drafts: #%d has no draft waiting\n", id)
+		return 1
+	}
+
+	guard := guardInterrupts("Tend cancelled; nothing was saved.")
+	defer guard.Stop()
+
+	if _, err := tendThought(st, guard.reader(bufio.NewReader(os.Stdin)), guard, thought, draft); err != nil {
+		if errors.Is(err, errInterrupted) {
+			guard.report()
+			return 130
+		}
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// discardDraftFor drops the draft kept for a thought, which may since have been purged.
+func discardDraftFor(ref string) int {
+	uid := ref
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+
+	if id, err := st.ResolveThoughtRef(ref); err == nil {
+		thought, _, err := st.GetThought(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+			return 1
+		}
+		uid = thought.UID
+	}
+
+	draft, err := loadDraft(uid)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+	if draft == nil {
+		fmt.Fprintf(os.Stderr, "drafts: no draft waiting for %s\n", ref)
+		return 1
+	}
+	if err := discardDraft(uid); err != nil {
+		fmt.Fprintf(os.Stderr, "drafts: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Discarded the draft for %s.\n", ref)
+	return 0
+}
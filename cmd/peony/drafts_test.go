@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+	"github.com/divijg19/peony/internal/storage"
+)
+
+// useTempGarden points the database, and so the drafts directory, at a temporary directory.
+func useTempGarden(t *testing.T) *storage.Store {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "peony.db")
+	t.Setenv("PEONY_DB_PATH", dbPath)
+
+	db, err := storage.Open(dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	st, err := storage.New(db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	return st
+}
+
+func TestDraftRoundTrip(t *testing.T) {
+	useTempGarden(t)
+	base := tendDraft{Content: "before", Feeling: core.Feeling{Energy: intPtr(1)}, Tags: []string{"cabin"}}
+	updatedAt := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC)
+
+	if err := saveDraft("abc123", "edited text", newDraftBase(base, updatedAt, core.StateResting)); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	draft, err := loadDraft("abc123")
+	if err != nil || draft == nil {
+		t.Fatalf("load = %v, %v", draft, err)
+	}
+	if draft.Text != "edited text" || draft.Base == nil {
+		t.Fatalf("loaded %+v", draft)
+	}
+
+	got, expected, state := draft.basis(core.Thought{Content: "moved on", UpdatedAt: time.Now(), CurrentState: core.StateReleased})
+	if got.Content != "before" || !slices.Equal(got.Tags, base.Tags) || got.Feeling.Energy == nil || *got.Feeling.Energy != 1 {
+		t.Errorf("basis draft = %+v, want the draft it was written from", got)
+	}
+	if !expected.Equal(updatedAt) || state != core.StateResting {
+		t.Errorf("basis = %s, %s; want %s, %s", expected, state, updatedAt, core.StateResting)
+	}
+
+	if err := discardDraft("abc123"); err != nil {
+		t.Fatalf("discard: %v", err)
+	}
+	if draft, err := loadDraft("abc123"); err != nil || draft != nil {
+		t.Errorf("load after discard = %+v, %v; want none", draft, err)
+	}
+}
+
+func TestDraftBasisWithoutRecordedBase(t *testing.T) {
+	savedAt := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.UTC)
+	draft := savedDraft{UID: "abc123", SavedAt: savedAt, Text: "edited"}
+
+	unchanged := core.Thought{Content: "as kept", UpdatedAt: savedAt.Add(-time.Hour), CurrentState: core.StateCaptured}
+	got, expected, state := draft.basis(unchanged)
+	if got.Content != "as kept" || !expected.Equal(unchanged.UpdatedAt) || state != core.StateCaptured {
+		t.Errorf("basis of an unchanged thought = %+v, %s, %s", got, expected, state)
+	}
+
+	changed := core.Thought{Content: "changed since", UpdatedAt: savedAt.Add(time.Hour), CurrentState: core.StateCaptured}
+	got, expected, _ = draft.basis(changed)
+	if got.Content != "" || !expected.Equal(savedAt) {
+		t.Errorf("basis of a changed thought = %+v, %s; want nothing known, expecting %s", got, expected, savedAt)
+	}
+}
+
+func TestStaleDraftRaisesConflict(t *testing.T) {
+	st := useTempGarden(t)
+	id, uid, err := st.CreateThought("the first words", core.RestFor(time.Now(), time.Hour), core.Feeling{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	thought, _, err := st.GetThought(id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	// The tend fails and its edit is kept as a draft against the thought as it was read.
+	base := tendDraft{Content: thought.Content}
+	if err := saveDraft(uid, renderTendTemplate(tendDraft{Content: "my draft"}), newDraftBase(base, thought.UpdatedAt, thought.CurrentState)); err != nil {
+		t.Fatalf("save draft: %v", err)
+	}
+
+	// The thought is then edited elsewhere before the draft is picked up again.
+	if err := st.UpdateThoughtContent(id, "edited elsewhere", thought.UpdatedAt); err != nil {
+		t.Fatalf("edit elsewhere: %v", err)
+	}
+	current, _, err := st.GetThought(id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	draft, err := loadDraft(uid)
+	if err != nil || draft == nil {
+		t.Fatalf("load draft = %v, %v", draft, err)
+	}
+	restored, err := parseTendText(draft.Text)
+	if err != nil {
+		t.Fatalf("parse draft: %v", err)
+	}
+	draftBase, expected, _ := draft.basis(current)
+	if draftBase.Content != "the first words" {
+		t.Errorf("draft base content = %q, want the content it was written from", draftBase.Content)
+	}
+
+	err = st.CommitTend(id, storage.TendDecisions{Content: restored.Content, Expected: expected})
+	var conflict *storage.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("saving the stale draft = %v, want *storage.ConflictError", err)
+	}
+	if conflict.Current.Content != "edited elsewhere" {
+		t.Errorf("conflict shows %q, want the edit made elsewhere", conflict.Current.Content)
+	}
+}
@@ -1,11 +1,15 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
 )
 
 func buildEditorCommand(editor string, path string) (*exec.Cmd, error) {
@@ -69,16 +73,163 @@ func availableEditors() []string {
 	return editors
 }
 
-// OpenEditorWithTemplate opens a temp file in the user's editor, then parses and returns edited content and an optional note.
-func OpenEditorWithTemplate(initialContent string, initialNote string) (content *string, note *string, err error) {
-	// ContentHeader marks the start of the editable thought content section in the editor template.
-	ContentHeader := "--- content ---"
-	// NoteHeader marks the start of the optional note section in the editor template.
-	NoteHeader := "--- note ---"
+// tendDraft is the editable part of a thought as presented in the tend editor.
+type tendDraft struct {
+	Content string
+	Note    *string
+	Feeling core.Feeling
+	// Tags replaces the thought's tags; nil, as from a template without a tags field, keeps them.
+	Tags []string
+	// Next is what happens once the edit is saved: empty to ask afterwards, nextKeep to save
+	// without tending, or one of resolutionStates to tend the thought in the same pass.
+	Next string
+	// Rest is how long to rest when Next is rest, as written; empty leaves it to spacing.
+	Rest string
+}
+
+// Headers that split the tend editor template into sections.
+const (
+	// feelingHeader marks the optional valence/energy section in the editor template.
+	feelingHeader = "--- feeling ---"
+	// contentHeader marks the start of the editable thought content section in the editor template.
+	contentHeader = "--- content ---"
+	// noteHeader marks the start of the optional note section in the editor template.
+	noteHeader = "--- note ---"
+)
+
+// The front matter of the tend template: a block fenced by frontMatterFence lines at the top
+// of the file, holding "key: value" fields and "#" comments. Everything after it is the body.
+const (
+	frontMatterFence = "---"
+	// templateVersion is written as "peony: 2" and tells this layout from the original one,
+	// which had no front matter and stripped every "//" line.
+	templateVersion = "2"
+	// bodyEscape starts a body line that must be read literally. One is removed on reading, so a
+	// line of the thought that is itself a marker or starts with the escape can still be written.
+	bodyEscape = `\`
+	// nextKeep in the next field saves the edit without marking the thought tended.
+	nextKeep = "keep"
+)
+
+// errEmptyContent is returned when the editor was left with no thought in it.
+var errEmptyContent = errors.New("edited content is empty")
+
+// resolutionStates maps the next field of the template to the state a tended thought moves to.
+var resolutionStates = map[string]core.State{
+	"rest":    core.StateResting,
+	"evolve":  core.StateEvolved,
+	"release": core.StateReleased,
+	"archive": core.StateArchived,
+}
 
+// OpenEditorWithTemplate opens a temp file in the user's editor, then parses and returns the edited
+// content, an optional note and the thought's feeling, tags and next step.
+func OpenEditorWithTemplate(initial tendDraft) (*tendDraft, error) {
+	text, err := editText(renderTendTemplate(initial))
+	if err != nil {
+		return nil, err
+	}
+	return parseTendText(text)
+}
+
+// renderTendTemplate lays out a draft as the text shown in the tend editor.
+func renderTendTemplate(initial tendDraft) string {
+	initialNote := ""
+	if initial.Note != nil {
+		initialNote = *initial.Note
+	}
+
+	comments := []string{
+		"Peony tend. Lines starting with # are comments, but only between the --- lines.",
+		"valence: -2 (heavy) to +2 (light). energy: low, medium or high. Blank unsets them.",
+		"tags: words separated by commas, e.g. cabin, ideas. Blank removes them all.",
+		"next: blank to decide after saving, keep to save without tending, or",
+		"  rest, evolve, release or archive to tend it in this one pass.",
+		"rest: with next: rest, how long, e.g. 3d, 2w, next monday, 2027-01-15. Blank uses spacing.",
+		"Below the closing ---, the thought is kept exactly as written, comments included,",
+		"until a " + noteHeader + " line starts the optional note. To write a line that is",
+		noteHeader + " or starts with " + bodyEscape + ", put one extra " + bodyEscape + " in front of it.",
+	}
+	fields := append(feelingFields(initial.Feeling),
+		templateField{"tags", core.FormatTags(initial.Tags)},
+		templateField{"next", initial.Next},
+		templateField{"rest", initial.Rest},
+	)
+	body := escapeBody(initial.Content) + "\n" + noteHeader + "\n" + escapeBody(initialNote)
+	return renderTemplate(comments, fields, body)
+}
+
+// renderCaptureTemplate lays out the editor text for capturing a new thought. Only the feeling
+// can be set alongside the content; the rest comes with tending.
+func renderCaptureTemplate(content string, feeling core.Feeling) string {
+	comments := []string{
+		"Peony add. Lines starting with # are comments, but only between the --- lines.",
+		"valence: -2 (heavy) to +2 (light). energy: low, medium or high. Blank leaves them unset.",
+		"Below the closing ---, the thought is kept exactly as written, comments included.",
+		"To write a line that is " + noteHeader + " or starts with " + bodyEscape + ", put one extra " + bodyEscape + " in front of it.",
+	}
+	return renderTemplate(comments, feelingFields(feeling), escapeBody(content))
+}
+
+// templateField is one "key: value" line of the template front matter.
+type templateField struct {
+	key, value string
+}
+
+// feelingFields renders a feeling as the valence and energy fields of the front matter.
+func feelingFields(feeling core.Feeling) []templateField {
+	valence := ""
+	if feeling.Valence != nil {
+		valence = core.FormatValence(*feeling.Valence)
+	}
+	energy := ""
+	if feeling.Energy != nil {
+		energy = core.EnergyLabel(*feeling.Energy)
+	}
+	return []templateField{{"valence", valence}, {"energy", energy}}
+}
+
+// renderTemplate writes the front matter, with its comments, version line and fields, then body.
+func renderTemplate(comments []string, fields []templateField, body string) string {
+	var b strings.Builder
+	b.WriteString(frontMatterFence + "\n")
+	for _, comment := range comments {
+		b.WriteString("# " + comment + "\n")
+	}
+	b.WriteString("peony: " + templateVersion + "\n")
+	for _, field := range fields {
+		b.WriteString(field.key + ": " + field.value + "\n")
+	}
+	b.WriteString(frontMatterFence + "\n")
+	b.WriteString(body)
+	return b.String()
+}
+
+// escapeBody prefixes bodyEscape to every line that would otherwise be read as a marker or lose
+// a leading escape, so that parseTendText returns s unchanged.
+func escapeBody(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, bodyEscape) || isMarker(line, noteHeader) {
+			lines[i] = bodyEscape + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isMarker reports whether line is marker, ignoring trailing whitespace.
+func isMarker(line, marker string) bool {
+	return strings.TrimRight(line, " \t\r") == marker
+}
+
+// editText writes text to a temp file, opens it in the user's editor and returns what was saved.
+// It never returns less than it was given: if the editor fails, whatever the file holds by then
+// is returned with the error, or text itself when the file cannot be written or read back, so
+// the caller can always keep it as a draft.
+func editText(text string) (string, error) {
 	file, err := os.CreateTemp("", "peonyTend.txt")
 	if err != nil {
-		return nil, nil, err
+		return text, err
 	}
 	path := file.Name()
 
@@ -86,23 +237,21 @@ func OpenEditorWithTemplate(initialContent string, initialNote string) (content
 		os.Remove(path)
 	}()
 
-	templateContent := "// Peony tend — edit freely.\n// Thought is under the content header; note is optional.\n// If you remove the note header, everything will be treated as the thought.\n"
-
-	_, err = file.WriteString(templateContent + "\n\n" + ContentHeader + "\n" + initialContent + "\n" + NoteHeader + "\n" + initialNote)
+	_, err = file.WriteString(text)
 	if err != nil {
 		_ = file.Close()
-		return nil, nil, err
+		return text, err
 	}
 
 	err = file.Sync()
 	if err != nil {
 		_ = file.Close()
-		return nil, nil, err
+		return text, err
 	}
 
 	err = file.Close()
 	if err != nil {
-		return nil, nil, err
+		return text, err
 	}
 
 	var cmd *exec.Cmd
@@ -110,7 +259,7 @@ func OpenEditorWithTemplate(initialContent string, initialNote string) (content
 		configured := strings.TrimSpace(cfg.Editor)
 		cmd, err = buildEditorCommand(configured, path)
 		if err != nil {
-			return nil, nil, fmt.Errorf("configured editor not found: %w", err)
+			return text, fmt.Errorf("configured editor not found: %w", err)
 		}
 	} else {
 		editors := []string{os.Getenv("VISUAL"), os.Getenv("EDITOR"), "nano", "vim", "vi"}
@@ -122,24 +271,200 @@ func OpenEditorWithTemplate(initialContent string, initialNote string) (content
 			cmd = nil
 		}
 		if cmd == nil {
-			return nil, nil, fmt.Errorf("no editor found in $VISUAL/$EDITOR and no fallback (nano/vim/vi) is available")
+			return text, fmt.Errorf("no editor found in $VISUAL/$EDITOR and no fallback (nano/vim/vi) is available")
 		}
 	}
 
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	err = cmd.Run()
+	runErr := cmd.Run()
+
+	data, err := os.ReadFile(path)
+	if runErr != nil {
+		if err != nil {
+			return text, runErr
+		}
+		return string(data), fmt.Errorf("editor: %w", runErr)
+	}
 	if err != nil {
-		return nil, nil, err
+		return text, err
 	}
+	return string(data), nil
+}
 
-	data, err := os.ReadFile(path)
+// parseTendText reads a draft back out of the tend editor text. Text that opens with front
+// matter is read as the current template; anything else, such as a draft kept by an older
+// peony, is read in the original layout.
+func parseTendText(text string) (*tendDraft, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for idx, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if isMarker(line, frontMatterFence) {
+			return parseFrontMatterTemplate(lines[idx+1:])
+		}
+		break
+	}
+	return parseLegacyTendText(text)
+}
+
+// parseFrontMatterTemplate reads the lines that follow the opening fence of the template. All
+// problems in the front matter are reported together so they can be fixed in one go.
+func parseFrontMatterTemplate(lines []string) (*tendDraft, error) {
+	end := -1
+	for idx, line := range lines {
+		if isMarker(line, frontMatterFence) {
+			end = idx
+			break
+		}
+	}
+	if end == -1 {
+		return nil, fmt.Errorf("template: the settings block is not closed with a %s line", frontMatterFence)
+	}
+
+	draft, err := parseFrontMatter(lines[:end])
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
-	text := string(data)
 
+	body := lines[end+1:]
+	noteIndex := len(body)
+	for idx, line := range body {
+		if isMarker(line, noteHeader) {
+			noteIndex = idx
+			break
+		}
+	}
+
+	draft.Content = unescapeBody(body[:noteIndex])
+	if strings.TrimSpace(draft.Content) == "" {
+		return nil, errEmptyContent
+	}
+	if noteIndex < len(body) {
+		if note := unescapeBody(body[noteIndex+1:]); strings.TrimSpace(note) != "" {
+			draft.Note = &note
+		}
+	}
+	return draft, nil
+}
+
+// parseFrontMatter reads and validates the "key: value" fields of the template.
+func parseFrontMatter(lines []string) (*tendDraft, error) {
+	draft := &tendDraft{}
+	seen := make(map[string]bool)
+	var problems []error
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			problems = append(problems, fmt.Errorf("expected \"key: value\", got %q", trimmed))
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if seen[key] {
+			problems = append(problems, fmt.Errorf("%s: given more than once", key))
+			continue
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "peony":
+			if value != templateVersion {
+				err = fmt.Errorf("template version %q is not supported (this peony reads version %s)", value, templateVersion)
+			}
+		case "valence":
+			if value != "" {
+				var v int
+				v, err = core.ParseValence(value)
+				draft.Feeling.Valence = &v
+			}
+		case "energy":
+			if value != "" {
+				var e int
+				e, err = core.ParseEnergy(value)
+				draft.Feeling.Energy = &e
+			}
+		case "tags":
+			draft.Tags, err = core.ParseTags(value)
+		case "next":
+			draft.Next = strings.ToLower(value)
+			if _, ok := resolutionStates[draft.Next]; !ok && draft.Next != "" && draft.Next != nextKeep {
+				err = fmt.Errorf("unknown next step %q (want keep, rest, evolve, release or archive)", value)
+			}
+		case "rest":
+			draft.Rest = value
+		default:
+			err = fmt.Errorf("unknown field %q", key)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+
+	if !seen["peony"] {
+		problems = append(problems, fmt.Errorf("peony: the template version line is missing"))
+	}
+	if draft.Rest != "" {
+		if draft.Next != "rest" {
+			problems = append(problems, fmt.Errorf("rest: only used with next: rest"))
+		} else if _, err := core.ParseRest(draft.Rest, time.Now()); err != nil {
+			problems = append(problems, fmt.Errorf("rest: %w", err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("template: %w", errors.Join(problems...))
+	}
+	return draft, nil
+}
+
+// unescapeBody joins body lines, removing one leading bodyEscape from each line that has one, and
+// drops blank lines at either end.
+func unescapeBody(lines []string) string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		out = append(out, strings.TrimPrefix(line, bodyEscape))
+	}
+	return trimBlankLines(out)
+}
+
+// trimBlankLines joins lines, dropping blank lines at either end and trailing whitespace, while
+// keeping the indentation of the first line.
+func trimBlankLines(lines []string) string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), " \t\r")
+}
+
+// parseCaptureText reads a new thought back out of the capture editor text. Fields that only
+// make sense when tending are refused rather than silently dropped.
+func parseCaptureText(text string) (*tendDraft, error) {
+	draft, err := parseTendText(text)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case draft.Tags != nil || draft.Next != "" || draft.Rest != "":
+		return nil, fmt.Errorf("template: only valence and energy can be set when capturing; tags and next steps come with tending")
+	case draft.Note != nil:
+		return nil, fmt.Errorf("template: a note is kept with a tend, not at capture; escape a %q line in the thought as %s%s", noteHeader, bodyEscape, noteHeader)
+	}
+	return draft, nil
+}
+
+// parseLegacyTendText reads the original template layout, where every "//" line is a comment.
+func parseLegacyTendText(text string) (*tendDraft, error) {
 	rawLines := strings.Split(text, "\n")
 	lines := make([]string, 0, len(rawLines))
 	for _, ln := range rawLines {
@@ -162,13 +487,41 @@ func OpenEditorWithTemplate(initialContent string, initialNote string) (content
 		effectiveLines = append(effectiveLines, ln)
 	}
 
+	// The feeling section runs from its header to the next header; it is parsed and then removed
+	// so the content/note rules below see the same layout as before.
+	var feeling core.Feeling
+	feelingIndex := -1
+	for idx, line := range effectiveLines {
+		if line == feelingHeader {
+			feelingIndex = idx
+			break
+		}
+	}
+	if feelingIndex != -1 {
+		end := len(effectiveLines)
+		for idx := feelingIndex + 1; idx < len(effectiveLines); idx++ {
+			if effectiveLines[idx] == contentHeader || effectiveLines[idx] == noteHeader {
+				end = idx
+				break
+			}
+		}
+
+		var err error
+		feeling, err = parseFeelingLines(effectiveLines[feelingIndex+1 : end])
+		if err != nil {
+			return nil, err
+		}
+
+		effectiveLines = append(effectiveLines[:feelingIndex:feelingIndex], effectiveLines[end:]...)
+	}
+
 	contentIndex := -1
 	noteIndex := -1
 	for idx, line := range effectiveLines {
-		if line == ContentHeader && contentIndex == -1 {
+		if line == contentHeader && contentIndex == -1 {
 			contentIndex = idx
 		}
-		if line == NoteHeader && noteIndex == -1 {
+		if line == noteHeader && noteIndex == -1 {
 			noteIndex = idx
 		}
 	}
@@ -204,19 +557,52 @@ func OpenEditorWithTemplate(initialContent string, initialNote string) (content
 
 	contentText = strings.TrimSpace(contentText)
 	if contentText == "" {
-		return nil, nil, fmt.Errorf("edited content is empty")
+		return nil, errEmptyContent
 	}
 
-	c := contentText
-	content = &c
+	draft := &tendDraft{Content: contentText, Feeling: feeling}
 
 	noteText = strings.TrimSpace(noteText)
-	if noteText == "" {
-		note = nil
-	} else {
+	if noteText != "" {
 		n := noteText
-		note = &n
+		draft.Note = &n
 	}
 
-	return content, note, nil
+	return draft, nil
+}
+
+// parseFeelingLines reads "valence: <n>" and "energy: <level>" lines. Blank values leave a field unset.
+func parseFeelingLines(lines []string) (core.Feeling, error) {
+	var feeling core.Feeling
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return core.Feeling{}, fmt.Errorf("feeling: expected \"key: value\", got %q", strings.TrimSpace(line))
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "valence":
+			v, err := core.ParseValence(value)
+			if err != nil {
+				return core.Feeling{}, fmt.Errorf("feeling: %w", err)
+			}
+			feeling.Valence = &v
+		case "energy":
+			e, err := core.ParseEnergy(value)
+			if err != nil {
+				return core.Feeling{}, fmt.Errorf("feeling: %w", err)
+			}
+			feeling.Energy = &e
+		default:
+			return core.Feeling{}, fmt.Errorf("feeling: unknown field %q", strings.TrimSpace(key))
+		}
+	}
+	return feeling, nil
 }
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+func TestEscapeBodyRoundTrip(t *testing.T) {
+	tests := []string{
+		"plain thought",
+		`\starts with an escape`,
+		`\\starts with two`,
+		"---",
+		"--- note ---",
+		"--- note ---   ",
+		"# a heading, not a comment",
+		"first\n---\n# heading\n--- note ---\n\\tail",
+		"  indented first line\nsecond",
+	}
+	for _, body := range tests {
+		lines := strings.Split(escapeBody(body), "\n")
+		if got := unescapeBody(lines); got != strings.TrimRight(body, " ") {
+			t.Errorf("unescapeBody(escapeBody(%q)) = %q", body, got)
+		}
+	}
+}
+
+func TestTendTemplateRoundTrip(t *testing.T) {
+	note := "--- note ---\n\\ a note with markers\n# and a hash"
+	initial := tendDraft{
+		Content: "# title\n---\n--- note ---\n\\escaped\nlast line",
+		Note:    &note,
+		Feeling: core.Feeling{Valence: intPtr(-1), Energy: intPtr(2)},
+		Tags:    []string{"cabin", "ideas"},
+		Next:    "rest",
+		Rest:    "3d",
+	}
+
+	got, err := parseTendText(renderTendTemplate(initial))
+	if err != nil {
+		t.Fatalf("parseTendText: %v", err)
+	}
+	if got.Content != initial.Content {
+		t.Errorf("content = %q, want %q", got.Content, initial.Content)
+	}
+	if got.Note == nil || *got.Note != note {
+		t.Errorf("note = %v, want %q", got.Note, note)
+	}
+	if got.Feeling.Valence == nil || *got.Feeling.Valence != -1 || got.Feeling.Energy == nil || *got.Feeling.Energy != 2 {
+		t.Errorf("feeling = %+v, want valence -1 and energy 2", got.Feeling)
+	}
+	if !slices.Equal(got.Tags, initial.Tags) || got.Next != "rest" || got.Rest != "3d" {
+		t.Errorf("tags, next, rest = %v, %q, %q", got.Tags, got.Next, got.Rest)
+	}
+
+	capture, err := parseCaptureText(renderCaptureTemplate("---\n--- note ---", core.Feeling{}))
+	if err != nil {
+		t.Fatalf("parseCaptureText: %v", err)
+	}
+	if capture.Content != "---\n--- note ---" || capture.Note != nil {
+		t.Errorf("capture = %q, note %v", capture.Content, capture.Note)
+	}
+}
+
+func TestParseTendTextFrontMatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		check   func(*tendDraft) bool
+		wantErr string
+	}{
+		{
+			name: "absent fields keep tags and feeling unset",
+			text: "---\npeony: 2\n---\nthought\n",
+			check: func(d *tendDraft) bool {
+				return d.Tags == nil && d.Feeling.Valence == nil && d.Next == "" && d.Note == nil
+			},
+		},
+		{
+			name: "blank tags clear them",
+			text: "---\npeony: 2\ntags:\nvalence:\nnext:\n---\nthought\n",
+			check: func(d *tendDraft) bool {
+				return d.Tags != nil && len(d.Tags) == 0 && d.Feeling.Valence == nil && d.Next == ""
+			},
+		},
+		{
+			name:  "blank note is no note",
+			text:  "---\npeony: 2\n---\nthought\n--- note ---\n  \n",
+			check: func(d *tendDraft) bool { return d.Content == "thought" && d.Note == nil },
+		},
+		{
+			name:  "comments only count inside the front matter",
+			text:  "---\n# a comment\npeony: 2\n---\n# kept\n",
+			check: func(d *tendDraft) bool { return d.Content == "# kept" },
+		},
+		{
+			name:    "missing closing fence",
+			text:    "---\npeony: 2\nnext: keep\nthought\n",
+			wantErr: "not closed",
+		},
+		{
+			name:    "unknown template version",
+			text:    "---\npeony: 3\n---\nthought\n",
+			wantErr: `template version "3" is not supported`,
+		},
+		{
+			name:    "missing template version",
+			text:    "---\nnext: keep\n---\nthought\n",
+			wantErr: "version line is missing",
+		},
+		{
+			name:    "field given twice",
+			text:    "---\npeony: 2\nnext: keep\nnext: rest\n---\nthought\n",
+			wantErr: "given more than once",
+		},
+		{
+			name:    "unknown field",
+			text:    "---\npeony: 2\nmood: fine\n---\nthought\n",
+			wantErr: `unknown field "mood"`,
+		},
+		{
+			name:    "rest without next rest",
+			text:    "---\npeony: 2\nrest: 3d\n---\nthought\n",
+			wantErr: "only used with next: rest",
+		},
+		{
+			name:    "empty body",
+			text:    "---\npeony: 2\n---\n\n--- note ---\na note\n",
+			wantErr: errEmptyContent.Error(),
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseTendText(tt.text)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want it to mention %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !tt.check(got) {
+			t.Errorf("%s: got %+v", tt.name, got)
+		}
+	}
+}
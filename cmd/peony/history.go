@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// cmdHistory lists every saved revision of a thought's content.
+func cmdHistory(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "history: usage: `peony history <id>`")
+		return 2
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "history: %v\n", err)
+		return 1
+	}
+
+	id, err := st.ResolveThoughtRef(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "history: %v\n", err)
+		return 2
+	}
+
+	revisions, err := st.ListRevisions(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "history: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("#%d has %d revision(s)\n\n", id, len(revisions))
+	fmt.Printf("%-5s %-18s %-12s %s\n", "REV", "SAVED", "CHANGE", "OVERVIEW")
+	for i, rev := range revisions {
+		change := "captured"
+		if i > 0 {
+			added, removed := core.DiffStats(core.DiffWords(revisions[i-1].Content, rev.Content))
+			change = fmt.Sprintf("+%d -%d", added, removed)
+		}
+		fmt.Printf("%-5s %-18s %-12s %s\n",
+			fmt.Sprintf("r%d", rev.Number),
+			rev.CreatedAt.UTC().Format("2006-01-02 15:04Z"),
+			change,
+			revisionOverview(rev.Content),
+		)
+	}
+	return 0
+}
+
+// cmdDiff shows a word-level diff between two revisions of a thought.
+// With no revisions it compares the latest with the one before; with one, that revision with the latest.
+func cmdDiff(args []string) int {
+	if len(args) < 1 || len(args) > 3 {
+		fmt.Fprintln(os.Stderr, "diff: usage: `peony diff <id> [rev] [rev]`")
+		return 2
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+		return 1
+	}
+
+	id, err := st.ResolveThoughtRef(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+		return 2
+	}
+
+	revisions, err := st.ListRevisions(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+		return 1
+	}
+	latest := revisions[len(revisions)-1].Number
+
+	from, to := latest-1, latest
+	switch len(args) {
+	case 2:
+		if from, err = parseRevisionNumber(args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			return 2
+		}
+	case 3:
+		if from, err = parseRevisionNumber(args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			return 2
+		}
+		if to, err = parseRevisionNumber(args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			return 2
+		}
+	}
+	if len(args) == 1 && from < 1 {
+		fmt.Printf("#%d has only one revision; nothing to compare yet.\n", id)
+		return 0
+	}
+
+	before, err := st.GetRevision(id, from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+		return 1
+	}
+	after, err := st.GetRevision(id, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+		return 1
+	}
+
+	chunks := core.DiffWords(before.Content, after.Content)
+	added, removed := core.DiffStats(chunks)
+	fmt.Printf("#%d r%d → r%d  (+%d -%d words)\n\n", id, before.Number, after.Number, added, removed)
+	fmt.Println(renderWordDiff(chunks))
+	return 0
+}
+
+// parseRevisionNumber accepts a revision as "3" or "r3".
+func parseRevisionNumber(s string) (int, error) {
+	text := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "r")
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid revision %q (want e.g. 2 or r2)", s)
+	}
+	return n, nil
+}
+
+// renderWordDiff marks removed words as [-word-] and added words as {+word+}, like git's word diff.
+func renderWordDiff(chunks []core.DiffChunk) string {
+	var b strings.Builder
+	for i, c := range chunks {
+		// Keep trailing whitespace outside the markers so line breaks stay readable.
+		text := strings.TrimRight(c.Text, " \t\n")
+		trailing := c.Text[len(text):]
+		if c.Op == core.DiffDelete && i+1 < len(chunks) && chunks[i+1].Op == core.DiffInsert {
+			// A replacement reads as [-old-]{+new+}; the insertion brings its own spacing.
+			trailing = ""
+		}
+		if text == "" {
+			if c.Op != core.DiffDelete {
+				b.WriteString(c.Text)
+			}
+			continue
+		}
+		switch c.Op {
+		case core.DiffInsert:
+			b.WriteString("{+" + text + "+}" + trailing)
+		case core.DiffDelete:
+			b.WriteString("[-" + text + "-]" + trailing)
+		default:
+			b.WriteString(c.Text)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// revisionOverview flattens content to a single short line.
+func revisionOverview(s string) string {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "\n", " "))
+	const max = 50
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-1] + "…"
+}
+
+// viewGardenAsOf prints every thought as it stood at the moment at, optionally limited to one state.
+func viewGardenAsOf(at time.Time, filter string) int {
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "view: %v\n", err)
+		return 1
+	}
+
+	thoughts, err := st.ListThoughtsAsOf(at)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "view: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Your garden as of %s\n\n", at.Format("2006-01-02 15:04"))
+
+	counts := make(map[core.State]int)
+	shown := 0
+	for _, th := range thoughts {
+		counts[th.CurrentState]++
+		if filter != "" && string(th.CurrentState) != filter {
+			continue
+		}
+		if shown == 0 {
+			fmt.Printf("%-6s %-9s %-10s %-5s %-20s %s\n", "ID", "UID", "STATE", "TEND", "UPDATED", "OVERVIEW")
+		}
+		shown++
+		fmt.Printf("%-6d %-9s %-10s %-5d %-20s %s\n",
+			th.ID,
+			th.UID,
+			th.CurrentState,
+			th.TendCounter,
+			th.UpdatedAt.UTC().Format("2006-01-02 15:04"),
+			revisionOverview(th.Content),
+		)
+	}
+
+	if shown == 0 {
+		fmt.Println("Nothing was growing here yet.")
+		return 0
+	}
+
+	parts := make([]string, 0, len(counts))
+	for _, state := range []core.State{core.StateCaptured, core.StateResting, core.StateTended, core.StateEvolved, core.StateArchived, core.StateReleased} {
+		if n := counts[state]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, state))
+		}
+	}
+	fmt.Println()
+	fmt.Println(strings.Join(parts, ", "))
+	return 0
+}
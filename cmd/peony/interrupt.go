@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// errInterrupted is returned once Ctrl-C has asked to leave a guarded flow: by reads through
+// the guard's reader, and by run when the signal arrived while its step was running.
+var errInterrupted = errors.New("interrupted")
+
+// interruptMode decides what a Ctrl-C does at a given point of an interactive flow.
+type interruptMode int
+
+const (
+	// interruptLeave ends the flow at its next prompt; safe while decisions are only staged in memory.
+	interruptLeave interruptMode = iota
+	// interruptIgnore drops the signal, e.g. while an editor owns the terminal.
+	interruptIgnore
+	// interruptDefer waits for the current step (such as a commit) to finish, then ends the flow.
+	interruptDefer
+)
+
+// interruptGuard turns SIGINT into a clean exit with a short message instead of a stack of
+// half-finished prompts. The signal is handed back to the flow as errInterrupted, which the
+// command reports and turns into exit status 130.
+type interruptGuard struct {
+	mu      sync.Mutex
+	mode    interruptMode
+	pending bool
+	message string
+	signals chan os.Signal
+	leaving chan struct{}
+	done    chan struct{}
+}
+
+// guardInterrupts starts handling SIGINT. message is printed when the flow is left early.
+func guardInterrupts(message string) *interruptGuard {
+	g := &interruptGuard{
+		message: message,
+		signals: make(chan os.Signal, 1),
+		leaving: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	signal.Notify(g.signals, os.Interrupt)
+	go g.watch()
+	return g
+}
+
+func (g *interruptGuard) watch() {
+	for {
+		select {
+		case <-g.signals:
+			g.mu.Lock()
+			switch g.mode {
+			case interruptLeave:
+				g.leave(g.message)
+			case interruptDefer:
+				g.pending = true
+			}
+			g.mu.Unlock()
+		case <-g.done:
+			return
+		}
+	}
+}
+
+// leave records message and wakes any read waiting on the guard. Only the first call counts.
+// g.mu must be held.
+func (g *interruptGuard) leave(message string) {
+	select {
+	case <-g.leaving:
+	default:
+		g.message = message
+		close(g.leaving)
+	}
+}
+
+// left reports whether the flow has been asked to end.
+func (g *interruptGuard) left() bool {
+	select {
+	case <-g.leaving:
+		return true
+	default:
+		return false
+	}
+}
+
+// run executes fn in the given mode, then returns to interruptLeave. When the flow was asked to
+// end by the time fn succeeds, including by a signal deferred while fn ran, run returns
+// errInterrupted; whatever fn did is kept.
+func (g *interruptGuard) run(mode interruptMode, fn func() error) error {
+	g.mu.Lock()
+	g.mode = mode
+	g.mu.Unlock()
+
+	err := fn()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.mode = interruptLeave
+	if g.pending {
+		g.pending = false
+		g.leave("Stopped. Everything saved so far is kept.")
+	}
+	if err == nil && g.left() {
+		return errInterrupted
+	}
+	return err
+}
+
+// reader wraps r so that a read waiting for input returns errInterrupted as soon as the flow
+// is asked to end.
+func (g *interruptGuard) reader(r *bufio.Reader) *bufio.Reader {
+	return bufio.NewReader(&guardedReader{src: r, guard: g})
+}
+
+// report prints why the flow ended early.
+func (g *interruptGuard) report() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, g.message)
+}
+
+// Stop restores the default SIGINT behaviour.
+func (g *interruptGuard) Stop() {
+	signal.Stop(g.signals)
+	close(g.done)
+}
+
+// guardedReader reads from src in the background so a Ctrl-C can end the wait.
+type guardedReader struct {
+	src   *bufio.Reader
+	guard *interruptGuard
+}
+
+func (r *guardedReader) Read(p []byte) (int, error) {
+	if r.guard.left() {
+		return 0, errInterrupted
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+	buf := make([]byte, len(p))
+	read := make(chan result, 1)
+	go func() {
+		n, err := r.src.Read(buf)
+		read <- result{n, err}
+	}()
+
+	select {
+	case res := <-read:
+		return copy(p, buf[:res.n]), res.err
+	case <-r.guard.leaving:
+		// The abandoned read is left to finish on its own; the flow is ending anyway.
+		return 0, errInterrupted
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
@@ -28,16 +27,40 @@ Commands:
   version, -v    Show version
   add, a         Capture a thought
   view, v        View the list of thoughts or a thought by id
+  history        List the saved revisions of a thought
+  diff           Show what changed between two revisions
   tend, t        List thoughts which are ready to be tended
-  release, r     Clears a thought from peony
+  drafts         List or resume edits kept from unfinished tends
+  rest           Intentionally defer a thought
+  archive        Keep a thought in long-term memory
+  revive         Bring an evolved, archived or released thought back
+  release, r     Lets a thought go, keeping its history
+  purge          Permanently deletes released thoughts
+  pause, resume  Step away without thoughts ripening while you are gone
+  rebuild        Check thoughts against their history and repair them
+  db status      Show the database's schema version and migrations
   evolve, e      Passes a thought into peony wider integration
   config, c      View and edit defaults for peony
 
 Syntax:
-  peony add [content]
+  peony add [--settle duration] [--valence n] [--energy level] [content]
   peony view [id]
+  peony view [id] --rev <revision>
+  peony view [id | filter] --as-of <date>
   peony view [filter]
-  peony tend [id]
+  peony history <id>
+  peony diff <id> [rev] [rev]
+  peony tend [id | --session]
+  peony drafts [id | discard <id>]
+  peony rest <id> [--for duration | --until date] [--note text]
+  peony archive <id> [--note text]
+  peony revive <id> [--note text]
+  peony release <id> [--note text]
+  peony purge <id | --before date>
+  peony pause
+  peony resume
+  peony rebuild [id] [--repair]
+  peony db status
   peony config [setting]
 
 Examples:
@@ -46,43 +69,163 @@ Examples:
   peony view 12
   peony view --archived
 
+Thoughts can be referred to by their display ID (12) or their permanent
+UID (k7m2xq9a). Display IDs never change; a purge leaves a gap.
+
+Durations accept forms like 18h, 3d, 2w, 1mo, 1w2d or "in a fortnight".
+Dates accept 2027-01-15, today, tomorrow, "next monday" or "next month".
+
 For detailed help on a command:
   peony help <command>
 `)
 }
 
-// openStore opens the SQLite-backed store and returns a close function.
-func openStore() (*storage.Store, func(), error) {
+// sharedStore is opened at most once per invocation and shared by the ready notice and the command.
+var sharedStore struct {
+	st    *storage.Store
+	close func()
+}
+
+// openStore returns the invocation's SQLite-backed store, opening it on first use.
+// Commands never close it; exit does, once, before the process ends.
+func openStore() (*storage.Store, error) {
+	if sharedStore.st != nil {
+		return sharedStore.st, nil
+	}
+
 	var err error
 
 	var dbPath string
 	dbPath, err = storage.ResolveDBPath()
 	if err != nil {
-		return nil, nil, fmt.Errorf("resolve db path: %w", err)
+		return nil, fmt.Errorf("resolve db path: %w", err)
 	}
 
 	sqlDB, err := storage.Open(dbPath)
 	if err != nil {
-		return nil, nil, fmt.Errorf("open db: %w", err)
+		return nil, fmt.Errorf("open db: %w", err)
 	}
 
 	var st *storage.Store
 	st, err = storage.New(sqlDB)
 	if err != nil {
 		_ = sqlDB.Close()
-		return nil, nil, fmt.Errorf("new store: %w", err)
+		return nil, fmt.Errorf("new store: %w", err)
 	}
 
-	closeFn := func() {
+	sharedStore.st = st
+	sharedStore.close = func() {
 		_ = sqlDB.Close()
 	}
-	return st, closeFn, nil
+	return st, nil
+}
+
+// exit closes the shared store, if one was opened, and ends the process with code.
+func exit(code int) {
+	if sharedStore.close != nil {
+		sharedStore.close()
+	}
+	os.Exit(code)
 }
 
 // cmdAdd captures a thought and appends the initial captured event.
 func cmdAdd(args []string) int {
-	content := strings.TrimSpace(strings.Join(args, " "))
-	if content == "" {
+	var (
+		settleArg string
+		feeling   core.Feeling
+		words     []string
+		// edit opens the editor for the content; editSet records that a flag chose it.
+		edit, editSet bool
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--settle":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "add: --settle needs a duration")
+				return 2
+			}
+			settleArg = args[i+1]
+			i++
+		case "--valence":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "add: --valence needs a value from -2 to +2")
+				return 2
+			}
+			v, err := core.ParseValence(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "add: %v\n", err)
+				return 2
+			}
+			feeling.Valence = &v
+			i++
+		case "--energy":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "add: --energy needs low, medium or high")
+				return 2
+			}
+			e, err := core.ParseEnergy(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "add: %v\n", err)
+				return 2
+			}
+			feeling.Energy = &e
+			i++
+		case "--edit":
+			edit, editSet = true, true
+		case "--no-edit":
+			edit, editSet = false, true
+		default:
+			words = append(words, arg)
+		}
+	}
+
+	settle := core.DefaultRest(time.Now())
+	if settleArg != "" {
+		d, err := core.ParseSpan(settleArg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "add: invalid settle duration: %v\n", err)
+			return 2
+		}
+		settle = core.RestFor(time.Now(), d)
+		settle.Reason = "settle for " + core.FormatSpan(d)
+	}
+
+	content := strings.TrimSpace(strings.Join(words, " "))
+	piped := stdinIsPiped()
+	if !editSet {
+		cfg, _ := loadRuntimeConfig()
+		edit = cfg.AddWithEditor && content == "" && !piped
+	}
+
+	switch {
+	case edit && piped:
+		fmt.Fprintln(os.Stderr, "add: --edit needs a terminal; pipe the content without --edit instead")
+		return 2
+
+	case edit:
+		draft, err := captureInEditor(bufio.NewReader(os.Stdin), content, feeling)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "add: %v\n", err)
+			return 1
+		}
+		if draft == nil {
+			fmt.Println("The editor was left empty; nothing was captured.")
+			return 0
+		}
+		content = draft.Content
+		feeling = draft.Feeling
+
+	case content == "" && piped:
+		var err error
+		content, err = readPipedContent(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "add: %v\n", err)
+			return 1
+		}
+
+	case content == "":
 		fmt.Print("What would you like to hold? ")
 		reader := bufio.NewReader(os.Stdin)
 		line, err := reader.ReadString('\n')
@@ -91,6 +234,22 @@ func cmdAdd(args []string) int {
 			return 1
 		}
 		content = strings.TrimSpace(line)
+
+		// Interactive capture gently offers the feeling fields, always skippable.
+		if content != "" && feeling.Valence == nil {
+			feeling.Valence, err = promptOptionalInt(reader, "How does it feel? (-2 heavy … +2 light, enter to skip)", core.ParseValence)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "add: %v\n", err)
+				return 1
+			}
+		}
+		if content != "" && feeling.Energy == nil {
+			feeling.Energy, err = promptOptionalInt(reader, "How much energy does it hold? (low/medium/high, enter to skip)", core.ParseEnergy)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "add: %v\n", err)
+				return 1
+			}
+		}
 	}
 
 	if content == "" {
@@ -98,41 +257,85 @@ func cmdAdd(args []string) int {
 		return 1
 	}
 
-	st, closeDB, err := openStore()
+	st, err := openStore()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "add: %v\n", err)
 		return 1
 	}
-	defer closeDB()
 
 	var id int64
-	id, err = st.CreateThought(content)
+	var uid string
+	id, uid, err = st.CreateThought(content, settle, feeling)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "add: %v\n", err)
 		return 1
 	}
 
-	next := core.StateCaptured
-	err = st.AppendEvent(id, "captured", nil, &next, nil)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "add: append event: %v\n", err)
-		return 1
-	}
-
-	fmt.Printf("Saved as #%d\n", id)
+	fmt.Printf("Saved as #%d (%s)\n", id, uid)
 	return 0
 }
 
 // cmdView shows a paginated list of thoughts or a single thought with its event history.
 func cmdView(args []string) int {
+	// --rev shows a thought's content as it was at an earlier revision;
+	// --as-of replays one thought or the whole garden to an earlier moment.
+	var revArg, asOfArg string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--rev", "--as-of":
+			if i+1 >= len(args) {
+				fmt.Fprintf(os.Stderr, "view: %s needs a value\n", args[i])
+				return 2
+			}
+			if args[i] == "--rev" {
+				revArg = args[i+1]
+			} else {
+				asOfArg = args[i+1]
+			}
+			i++
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	args = rest
+	if revArg != "" && len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "view: usage: `peony view <id> --rev <revision>`")
+		return 2
+	}
+	if revArg != "" && asOfArg != "" {
+		fmt.Fprintln(os.Stderr, "view: use either --rev or --as-of, not both")
+		return 2
+	}
+
+	var asOf *time.Time
+	if asOfArg != "" {
+		at, err := core.ParseAsOf(asOfArg, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "view: --as-of: %v\n", err)
+			return 2
+		}
+		asOf = &at
+
+		if len(args) == 0 || (len(args) == 1 && isStateFilter(strings.TrimPrefix(args[0], "--"))) {
+			filter := ""
+			if len(args) == 1 {
+				filter = strings.TrimPrefix(args[0], "--")
+			}
+			return viewGardenAsOf(at, filter)
+		}
+		if len(args) != 1 {
+			fmt.Fprintln(os.Stderr, "view: usage: `peony view [id | filter] --as-of <date>`")
+			return 2
+		}
+	}
 
 	if len(args) == 0 {
-		st, closeDB, err := openStore()
+		st, err := openStore()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "view: %v\n", err)
 			return 1
 		}
-		defer closeDB()
 
 		reader := bufio.NewReader(os.Stdin)
 		pageSize := 10
@@ -166,10 +369,11 @@ func cmdView(args []string) int {
 			}
 
 			fmt.Printf("Page %d\n", page+1)
-			fmt.Printf("%-6s %-10s %-5s %-20s %s\n", "ID", "STATE", "TEND", "UPDATED", "OVERVIEW")
+			fmt.Printf("%-6s %-9s %-10s %-5s %-20s %s\n", "ID", "UID", "STATE", "TEND", "UPDATED", "OVERVIEW")
 			for _, th := range thoughts {
-				fmt.Printf("%-6d %-10s %-5d %-20s %s\n",
+				fmt.Printf("%-6d %-9s %-10s %-5d %-20s %s\n",
 					th.ID,
+					th.UID,
 					th.CurrentState,
 					th.TendCounter,
 					th.UpdatedAt.UTC().Format("2006-01-02 15:04"),
@@ -199,23 +403,60 @@ func cmdView(args []string) int {
 		}
 	}
 	if len(args) == 1 {
-		if id, err := strconv.ParseInt(args[0], 10, 64); err == nil && id > 0 {
-			st, closeDB, err := openStore()
+		if !isStateFilter(strings.TrimPrefix(args[0], "--")) {
+			st, err := openStore()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "view: %v\n", err)
+				return 1
+			}
+
+			id, err := st.ResolveThoughtRef(args[0])
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "view: %v\n", err)
 				return 1
 			}
-			defer closeDB()
 
-			thought, events, err := st.GetThought(id)
+			var thought core.Thought
+			var events []core.Event
+			if asOf != nil {
+				thought, events, err = st.GetThoughtAsOf(id, *asOf)
+			} else {
+				thought, events, err = st.GetThought(id)
+			}
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "view: %v\n", err)
 				return 1
 			}
 
-			fmt.Printf("#%d  %s  (tends: %d)\n", thought.ID, thought.CurrentState, thought.TendCounter)
+			contentHeading := "CONTENT"
+			content := thought.Content
+			if revArg != "" {
+				number, err := parseRevisionNumber(revArg)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "view: %v\n", err)
+					return 2
+				}
+				revisions, err := st.ListRevisions(id)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "view: %v\n", err)
+					return 1
+				}
+				if number > len(revisions) || revisions[number-1].Number != number {
+					fmt.Fprintf(os.Stderr, "view: #%d has no revision %d (it has %d)\n", id, number, len(revisions))
+					return 1
+				}
+				revision := revisions[number-1]
+				contentHeading = fmt.Sprintf("CONTENT (revision %d of %d, saved %s)", revision.Number, len(revisions), revision.CreatedAt.UTC().Format("2006-01-02 15:04Z"))
+				content = revision.Content
+			}
 
 			now := time.Now().UTC()
+			if asOf != nil {
+				now = asOf.UTC()
+				fmt.Printf("As of %s\n", asOf.Format("2006-01-02 15:04"))
+			}
+
+			fmt.Printf("#%d  %s  %s  (tends: %d)\n", thought.ID, thought.UID, thought.CurrentState, thought.TendCounter)
 
 			formatShortUTC := func(t time.Time) string {
 				return t.UTC().Format("2006-01-02 15:04Z")
@@ -252,13 +493,19 @@ func cmdView(args []string) int {
 			switch thought.CurrentState {
 			case core.StateCaptured, core.StateResting:
 				eligible := core.EligibleToSurface(thought, now)
-				if eligible {
+				if thought.EligibilityAt.IsZero() {
+					fmt.Println("Eligible: unknown")
+				} else if eligible {
 					fmt.Println("Eligible: yes")
 				} else {
 					fmt.Printf("Eligible: %s (at %s)\n", formatRelative(thought.EligibilityAt, now), formatShortUTC(thought.EligibilityAt))
 				}
 			case core.StateTended:
-				fmt.Println("Needs resolution: rest/evolve/release/archive")
+				next := make([]string, 0)
+				for _, state := range core.NextStates(core.StateTended) {
+					next = append(next, string(state))
+				}
+				fmt.Printf("Needs resolution: %s\n", strings.Join(next, "/"))
 			case core.StateEvolved, core.StateReleased, core.StateArchived:
 				fmt.Printf("Terminal: %s\n", thought.CurrentState)
 			default:
@@ -266,23 +513,41 @@ func cmdView(args []string) int {
 			}
 
 			fmt.Println()
-			fmt.Println("CONTENT")
-			fmt.Println(thought.Content)
+			fmt.Println(contentHeading)
+			fmt.Println(content)
 
 			fmt.Println()
 			fmt.Println("META")
 			fmt.Printf("Created:  %s (%s)\n", formatShortUTC(thought.CreatedAt), formatRelative(thought.CreatedAt, now))
 			fmt.Printf("Updated:  %s (%s)\n", formatShortUTC(thought.UpdatedAt), formatRelative(thought.UpdatedAt, now))
 			fmt.Printf("Eligible: %s (%s)\n", formatShortUTC(thought.EligibilityAt), formatRelative(thought.EligibilityAt, now))
+			switch thought.CurrentState {
+			case core.StateCaptured, core.StateResting:
+				for i := len(events) - 1; i >= 0; i-- {
+					ev := events[i]
+					if ev.EligibilityAt == nil || ev.Detail == nil {
+						continue
+					}
+					if ev.EligibilityAt.Equal(thought.EligibilityAt) {
+						fmt.Printf("Why:      %s\n", *ev.Detail)
+					}
+					break
+				}
+			case core.StateTended:
+				fmt.Printf("Next rest: %s\n", core.ResurfacePolicy.Explain(core.SettleDuration, thought.TendCounter))
+			}
 
 			if thought.LastTendedAt != nil {
 				fmt.Printf("Last tended: %s (%s)\n", formatShortUTC(*thought.LastTendedAt), formatRelative(*thought.LastTendedAt, now))
 			}
 			if thought.Valence != nil {
-				fmt.Printf("Valence: %d\n", *thought.Valence)
+				fmt.Printf("Valence: %s\n", core.FormatValence(*thought.Valence))
 			}
 			if thought.Energy != nil {
-				fmt.Printf("Energy: %d\n", *thought.Energy)
+				fmt.Printf("Energy: %s\n", core.EnergyLabel(*thought.Energy))
+			}
+			if len(thought.Tags) > 0 {
+				fmt.Printf("Tags: %s\n", core.FormatTags(thought.Tags))
 			}
 
 			if len(events) > 0 {
@@ -312,6 +577,13 @@ func cmdView(args []string) int {
 					}
 
 					fmt.Printf("- %s  %s%s\n", at, ev.Kind, transition)
+					if ev.Detail != nil && strings.TrimSpace(*ev.Detail) != "" {
+						if ev.EligibilityAt != nil {
+							fmt.Printf("  %s (eligible %s)\n", strings.TrimSpace(*ev.Detail), formatShortUTC(*ev.EligibilityAt))
+						} else {
+							fmt.Printf("  %s\n", strings.TrimSpace(*ev.Detail))
+						}
+					}
 					if ev.Note != nil && strings.TrimSpace(*ev.Note) != "" {
 						fmt.Printf("  note: %s\n", strings.TrimSpace(*ev.Note))
 					}
@@ -324,19 +596,15 @@ func cmdView(args []string) int {
 			filter = after
 		}
 
-		switch filter {
-		case "captured", "resting", "tended", "evolved", "released", "archived":
-
-		default:
+		if !isStateFilter(filter) {
 			fmt.Fprintln(os.Stderr, "view: invalid filter")
 			return 2
 		}
-		st, closeDB, err := openStore()
+		st, err := openStore()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "view: %v\n", err)
 			return 1
 		}
-		defer closeDB()
 
 		reader := bufio.NewReader(os.Stdin)
 		pageSize := 10
@@ -370,10 +638,11 @@ func cmdView(args []string) int {
 			}
 
 			fmt.Printf("Page %d\n", page+1)
-			fmt.Printf("%-6s %-10s %-5s %-20s %s\n", "ID", "STATE", "TEND", "UPDATED", "OVERVIEW")
+			fmt.Printf("%-6s %-9s %-10s %-5s %-20s %s\n", "ID", "UID", "STATE", "TEND", "UPDATED", "OVERVIEW")
 			for _, th := range thoughts {
-				fmt.Printf("%-6d %-10s %-5d %-20s %s\n",
+				fmt.Printf("%-6d %-9s %-10s %-5d %-20s %s\n",
 					th.ID,
+					th.UID,
 					th.CurrentState,
 					th.TendCounter,
 					th.UpdatedAt.UTC().Format("2006-01-02 15:04"),
@@ -405,17 +674,32 @@ func cmdView(args []string) int {
 	return 0
 }
 
+// isStateFilter reports whether s names a lifecycle state usable as a view filter.
+func isStateFilter(s string) bool {
+	switch core.State(s) {
+	case core.StateCaptured, core.StateResting, core.StateTended, core.StateEvolved, core.StateReleased, core.StateArchived:
+		return true
+	default:
+		return false
+	}
+}
+
 // cmdTend lists eligible thoughts or runs the interactive tend flow for a specific thought ID.
 func cmdTend(args []string) int {
-	if len(args) == 0 {
-		st, closeDB, err := openStore()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
-			return 1
-		}
-		defer closeDB()
+	reader := bufio.NewReader(os.Stdin)
 
-		reader := bufio.NewReader(os.Stdin)
+	// A thought left tended by an interrupted run is offered first.
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tend: %v\n", err)
+		return 1
+	}
+	if err := offerPendingResolutions(st, reader); err != nil {
+		fmt.Fprintf(os.Stderr, "tend: %v\n", err)
+		return 1
+	}
+
+	if len(args) == 0 {
 		pageSize := 10
 		page := 0
 
@@ -438,6 +722,21 @@ func cmdTend(args []string) int {
 			}
 
 			if len(thoughts) == 0 {
+				if page == 0 && !core.Windows.Open(time.Now()) {
+					fmt.Printf("Thoughts are resting until a reflection window opens (%s).\n", core.Windows.Describe())
+					return 0
+				}
+				if page == 0 && core.DailyBudget > 0 {
+					held, err := st.CountHeldBack()
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "tend: %v\n", err)
+						return 1
+					}
+					if held > 0 {
+						fmt.Printf("Nothing more is offered today (daily budget of %d); %d ripe thought(s) wait quietly.\n", core.DailyBudget, held)
+						return 0
+					}
+				}
 				if page == 0 {
 					fmt.Println("No thoughts yet.")
 					return 0
@@ -447,10 +746,11 @@ func cmdTend(args []string) int {
 			}
 
 			fmt.Printf("Page %d\n", page+1)
-			fmt.Printf("%-6s %-10s %-5s %-20s %s\n", "ID", "STATE", "TEND", "UPDATED", "OVERVIEW")
+			fmt.Printf("%-6s %-9s %-10s %-5s %-20s %s\n", "ID", "UID", "STATE", "TEND", "UPDATED", "OVERVIEW")
 			for _, th := range thoughts {
-				fmt.Printf("%-6d %-10s %-5d %-20s %s\n",
+				fmt.Printf("%-6d %-9s %-10s %-5d %-20s %s\n",
 					th.ID,
+					th.UID,
 					th.CurrentState,
 					th.TendCounter,
 					th.UpdatedAt.UTC().Format("2006-01-02 15:04"),
@@ -480,96 +780,279 @@ func cmdTend(args []string) int {
 		}
 	}
 
+	if len(args) == 1 && args[0] == "--session" {
+		return runTendSession(reader)
+	}
+
 	if len(args) == 1 {
-		id, err := strconv.ParseInt(args[0], 10, 64)
-		if err != nil || id <= 0 {
-			fmt.Fprintln(os.Stderr, "tend: invalid id")
+		id, err := st.ResolveThoughtRef(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
 			return 2
 		}
 
-		st, closeDB, err := openStore()
+		thought, _, err := st.GetTendThought(id)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
 			return 1
 		}
-		defer closeDB()
 
-		thought, _, err := st.GetTendThought(id)
+		draft, err := offerDraft(reader, thought)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
 			return 1
 		}
 
-		reader := bufio.NewReader(os.Stdin)
+		guard := guardInterrupts("Tend cancelled; nothing was saved.")
+		defer guard.Stop()
 
-		editedContent, editedNote, err := OpenEditorWithTemplate(thought.Content, "")
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "tend: edit: %v\n", err)
+		if _, err := tendThought(st, guard.reader(reader), guard, thought, draft); err != nil {
+			if errors.Is(err, errInterrupted) {
+				guard.report()
+				return 130
+			}
+			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
 			return 1
 		}
+		return 0
+	}
+
+	fmt.Fprintln(os.Stderr, "tend: expected at most one ID, or --session")
+	return 2
+}
+
+// tendOutcome records what happened to a thought during one pass through the tend flow.
+type tendOutcome struct {
+	// Saved is true when edits to content or feeling were kept.
+	Saved bool
+	// Tended is true when the thought was marked tended; Next is the state it moved on to.
+	Tended bool
+	Next   core.State
+}
+
+// tendThought runs the interactive tend flow for thought: edit, confirm, mark tended and resolve.
+// Every decision is staged and saved in one transaction at the end, so leaving early changes nothing.
+// The editor text is kept as a draft until the tend is saved or declined; a non-nil draft
+// reopens the editor with that text instead of the thought as saved. A Ctrl-C during the save
+// returns the outcome of the save together with errInterrupted.
+func tendThought(st *storage.Store, reader *bufio.Reader, guard *interruptGuard, thought core.Thought, draft *savedDraft) (tendOutcome, error) {
+	var outcome tendOutcome
+
+	base := tendDraft{
+		Content: thought.Content,
+		Feeling: core.Feeling{Valence: thought.Valence, Energy: thought.Energy},
+		Tags:    thought.Tags,
+	}
+	template := renderTendTemplate(base)
+	text := template
+	// A restored draft is saved against the thought as it was when the draft was written, so
+	// anything saved since is caught as a conflict rather than overwritten.
+	expected, baseState := thought.UpdatedAt, thought.CurrentState
+	if draft != nil {
+		text = draft.Text
+		base, expected, baseState = draft.basis(thought)
+	}
 
-		ok, err := promptYesNo(reader, "Are you satisfied with the changes?")
+	// Keep whatever the editor left behind, even when it failed, so nothing typed is lost.
+	kept := ""
+	edit := func() error {
+		var editErr error
+		err := guard.run(interruptIgnore, func() error {
+			text, editErr = editText(text)
+			return nil
+		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
-			return 1
+			return err
 		}
-		if !ok {
-			return 0
+		if text != "" && text != template {
+			if err := saveDraft(thought.UID, text, newDraftBase(base, expected, baseState)); err != nil {
+				fmt.Fprintf(os.Stderr, "Could not keep a draft: %v\n", err)
+			} else {
+				kept = fmt.Sprintf("; your edit was kept as a draft (peony drafts %d)", thought.ID)
+			}
 		}
+		return editErr
+	}
 
-		mark, err := promptYesNo(reader, "Do you want to mark this thought as tended? (Your note will be saved only if you say yes.)")
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
-			return 1
+	if err := edit(); err != nil {
+		return outcome, fmt.Errorf("edit: %w%s", err, kept)
+	}
+	edited, err := parseTendText(text)
+	for err != nil {
+		fmt.Fprintf(os.Stderr, "The edit could not be read:\n%s\n", indentLines(err.Error(), "  "))
+		again, promptErr := promptYesNo(reader, "Open the editor again to fix it?")
+		if promptErr != nil || !again {
+			return outcome, fmt.Errorf("edit: %w%s", err, kept)
 		}
-
-		if editedContent == nil {
-			return 1
+		if err := edit(); err != nil {
+			return outcome, fmt.Errorf("edit: %w%s", err, kept)
 		}
+		edited, err = parseTendText(text)
+	}
 
-		if err := st.UpdateThoughtContent(id, *editedContent); err != nil {
-			fmt.Fprintf(os.Stderr, "tend: save: %v\n", err)
-			return 1
-		}
+	if plan := describeTendPlan(*edited); plan != "" {
+		fmt.Println(plan)
+	}
+	ok, err := promptYesNo(reader, "Are you satisfied with the changes?")
+	if err != nil {
+		return outcome, fmt.Errorf("%w; nothing was saved%s", err, kept)
+	}
+	if !ok {
+		return outcome, discardDraft(thought.UID)
+	}
 
-		if !mark {
-			return 0
+	decisions := storage.TendDecisions{
+		Content:  edited.Content,
+		Feeling:  edited.Feeling,
+		Tags:     edited.Tags,
+		Expected: expected,
+	}
+	switch edited.Next {
+	case "":
+		decisions.Mark, err = promptYesNo(reader, "Do you want to mark this thought as tended? (Your note will be saved only if you say yes.)")
+		if err != nil {
+			return outcome, fmt.Errorf("%w; nothing was saved%s", err, kept)
+		}
+		if decisions.Mark {
+			decisions.Next, decisions.Rest, err = promptResolution(reader, thought.TendCounter+1)
+			if err != nil {
+				return outcome, fmt.Errorf("%w; nothing was saved%s", err, kept)
+			}
+		}
+	case nextKeep:
+	default:
+		// The template already settled where the thought goes, so a full tend needs no prompts.
+		decisions.Mark = true
+		decisions.Next = resolutionStates[edited.Next]
+		if edited.Rest != "" {
+			rest, err := core.ParseRest(edited.Rest, time.Now())
+			if err != nil {
+				return outcome, fmt.Errorf("rest: %w; nothing was saved%s", err, kept)
+			}
+			decisions.Rest = &rest
 		}
+	}
+	if decisions.Mark {
+		decisions.Note = edited.Note
+	}
 
-		if err := st.MarkThoughtTended(id, editedNote); err != nil {
-			fmt.Fprintf(os.Stderr, "tend: mark tended: %v\n", err)
-			return 1
+	// The editor may have been open a long time; if the thought was saved elsewhere meanwhile,
+	// ask which version to keep rather than overwrite it.
+	for {
+		err = guard.run(interruptDefer, func() error {
+			return st.CommitTend(thought.ID, decisions)
+		})
+		var conflict *storage.ConflictError
+		if !errors.As(err, &conflict) {
+			break
 		}
 
-		choice, err := promptChoice(reader, "What would you like to do next?", []string{"rest", "evolve", "release", "archive"})
+		resolved, err := resolveEditConflict(reader, guard, base, baseState, *edited, conflict)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
-			return 1
+			return outcome, fmt.Errorf("%w; nothing was saved%s", err, kept)
 		}
+		if resolved == nil {
+			fmt.Printf("Nothing was saved%s.\n", kept)
+			return outcome, nil
+		}
+		decisions.Content = resolved.Content
+		decisions.Feeling = resolved.Feeling
+		decisions.Tags = resolved.Tags
+		if decisions.Mark {
+			decisions.Note = resolved.Note
+		}
+		decisions.Expected = conflict.Current.UpdatedAt
+		base = tendDraft{
+			Content: conflict.Current.Content,
+			Feeling: core.Feeling{Valence: conflict.Current.Valence, Energy: conflict.Current.Energy},
+			Tags:    conflict.Current.Tags,
+		}
+		baseState = conflict.Current.CurrentState
+		edited = resolved
+	}
+	if err != nil && !errors.Is(err, errInterrupted) {
+		return outcome, fmt.Errorf("%w%s", err, kept)
+	}
+	if err := discardDraft(thought.UID); err != nil {
+		fmt.Fprintf(os.Stderr, "Saved, but %v\n", err)
+	}
 
-		var next core.State
-		switch choice {
-		case "rest":
-			next = core.StateResting
-		case "evolve":
-			next = core.StateEvolved
-		case "release":
-			next = core.StateReleased
-		case "archive":
-			next = core.StateArchived
-		default:
-			fmt.Fprintf(os.Stderr, "tend: unknown choice %q\n", choice)
-			return 2
+	outcome.Saved = true
+	outcome.Tended = decisions.Mark
+	outcome.Next = decisions.Next
+	return outcome, err
+}
+
+// describeTendPlan says what saving will do when the template already chose the next step.
+func describeTendPlan(draft tendDraft) string {
+	switch {
+	case draft.Next == "":
+		return ""
+	case draft.Next == nextKeep:
+		return "Saving keeps your edits without marking the thought tended."
+	case draft.Next == "rest" && draft.Rest != "":
+		return fmt.Sprintf("Saving marks the thought tended, then rests it (%s).", draft.Rest)
+	case draft.Next == "rest":
+		return "Saving marks the thought tended, then rests it for as long as spacing suggests."
+	default:
+		return fmt.Sprintf("Saving marks the thought tended, then moves it to %s.", resolutionStates[draft.Next])
+	}
+}
+
+// promptResolution asks where a tended thought goes next and, for resting, for how long.
+// tends is the thought's tend count including the tend being resolved.
+func promptResolution(reader *bufio.Reader, tends int) (core.State, *core.RestPeriod, error) {
+	choice, err := promptChoice(reader, "What would you like to do next?", []string{"rest", "evolve", "release", "archive"})
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch choice {
+	case "rest":
+		rest, err := promptRestPeriod(reader, tends)
+		if err != nil {
+			return "", nil, err
 		}
+		return core.StateResting, rest, nil
+	case "evolve":
+		return core.StateEvolved, nil, nil
+	case "release":
+		return core.StateReleased, nil, nil
+	case "archive":
+		return core.StateArchived, nil, nil
+	default:
+		return "", nil, fmt.Errorf("unknown choice %q", choice)
+	}
+}
 
-		if err := st.TransitionPostTendResolutionStrict(id, next, nil); err != nil {
-			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
-			return 1
+// offerPendingResolutions finds thoughts left tended without a resolution and offers to finish them.
+func offerPendingResolutions(st *storage.Store, reader *bufio.Reader) error {
+	pending, err := st.ListPendingTended()
+	if err != nil {
+		return err
+	}
+
+	for _, thought := range pending {
+		fmt.Printf("#%d %s was tended but never given a next step:\n", thought.ID, thought.UID)
+		fmt.Println(sessionPreview(thought.Content))
+		resume, err := promptYesNo(reader, "Would you like to resolve it now?")
+		if err != nil {
+			return err
+		}
+		if !resume {
+			continue
 		}
 
-		return 0
+		next, rest, err := promptResolution(reader, thought.TendCounter)
+		if err != nil {
+			return err
+		}
+		if err := st.TransitionPostTendResolutionStrict(thought.ID, next, nil, rest); err != nil {
+			return err
+		}
+		fmt.Printf("#%d is now %s.\n", thought.ID, next)
 	}
-	return 0
+	return nil
 }
 
 // promptYesNo asks a yes/no question on stdin and returns the user's choice.
@@ -592,6 +1075,48 @@ func promptYesNo(reader *bufio.Reader, question string) (bool, error) {
 	}
 }
 
+// promptRestPeriod asks how long a thought tended tends times should rest.
+// An empty answer returns nil, leaving the choice to the spacing policy.
+func promptRestPeriod(reader *bufio.Reader, tends int) (*core.RestPeriod, error) {
+	suggested := core.ResurfacePolicy.Interval(core.SettleDuration, tends)
+	for {
+		fmt.Printf("How long should it rest? (e.g. 3d, 2w, next monday, 2027-01-15; enter for %s): ", core.FormatSpan(suggested))
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("read: %w", err)
+		}
+		s := strings.TrimSpace(line)
+		if s == "" {
+			return nil, nil
+		}
+		rest, err := core.ParseRest(s, time.Now())
+		if err == nil {
+			return &rest, nil
+		}
+		fmt.Fprintf(os.Stderr, "Please enter a duration like 3d or 2w, or a date like 2027-01-15 (%v).\n", err)
+	}
+}
+
+// promptOptionalInt asks for a value parsed by parse; an empty answer returns nil.
+func promptOptionalInt(reader *bufio.Reader, question string, parse func(string) (int, error)) (*int, error) {
+	for {
+		fmt.Printf("%s: ", question)
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("read: %w", err)
+		}
+		s := strings.TrimSpace(line)
+		if s == "" {
+			return nil, nil
+		}
+		v, err := parse(s)
+		if err == nil {
+			return &v, nil
+		}
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+}
+
 // promptChoice asks the user to select one of the provided choices and returns the selected value.
 func promptChoice(reader *bufio.Reader, question string, choices []string) (string, error) {
 	if len(choices) == 0 {
@@ -616,28 +1141,53 @@ func promptChoice(reader *bufio.Reader, question string, choices []string) (stri
 	}
 }
 
-// cmdRelease permanently removes a thought (and its event history).
+// cmdRelease lets a thought go, keeping it and its history as a released tombstone.
 func cmdRelease(args []string) int {
-	if len(args) != 1 {
-		fmt.Fprintln(os.Stderr, "release: usage: `peony release <id>`")
-		return 2
+	var (
+		idArg           string
+		note            *string
+		unrecognizedArg string
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--note":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "release: --note needs a value")
+				return 2
+			}
+			n := args[i+1]
+			note = &n
+			i++
+		default:
+			if idArg == "" && !strings.HasPrefix(arg, "--") {
+				idArg = arg
+			} else {
+				unrecognizedArg = arg
+			}
+		}
 	}
 
-	id, err := strconv.ParseInt(args[0], 10, 64)
-	if err != nil || id <= 0 {
-		fmt.Fprintln(os.Stderr, "release: invalid id")
+	if idArg == "" || unrecognizedArg != "" {
+		fmt.Fprintln(os.Stderr, "release: usage: `peony release <id> [--note text]`")
 		return 2
 	}
 
-	st, closeDB, err := openStore()
+	st, err := openStore()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "release: %v\n", err)
 		return 1
 	}
-	defer closeDB()
+
+	id, err := st.ResolveThoughtRef(idArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "release: %v\n", err)
+		return 2
+	}
 
 	reader := bufio.NewReader(os.Stdin)
-	ok, err := promptYesNo(reader, fmt.Sprintf("Release thought #%d? This will delete it.", id))
+	ok, err := promptYesNo(reader, fmt.Sprintf("Release thought #%d? Its history will be kept.", id))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "release: %v\n", err)
 		return 1
@@ -646,13 +1196,20 @@ func cmdRelease(args []string) int {
 		return 0
 	}
 
-	if err := st.ReleaseThought(id); err != nil {
-		fmt.Fprintf(os.Stderr, "release: %v\n", err)
-		return 1
+	if note == nil {
+		fmt.Print("Any closing words? (optional, press enter to skip): ")
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "release: read: %v\n", err)
+			return 1
+		}
+		if n := strings.TrimSpace(line); n != "" {
+			note = &n
+		}
 	}
 
-	if err := st.ReindexThoughtIDs(); err != nil {
-		fmt.Fprintf(os.Stderr, "release: reindex ids: %v\n", err)
+	if err := st.ReleaseThought(id, note); err != nil {
+		fmt.Fprintf(os.Stderr, "release: %v\n", err)
 		return 1
 	}
 
@@ -660,17 +1217,381 @@ func cmdRelease(args []string) int {
 	return 0
 }
 
-// cmdEvolve displays evolved thoughts or marks a thought as evolved.
-func cmdEvolve(args []string) int {
-	if len(args) == 0 {
-		st, closeDB, err := openStore()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "evolve: %v\n", err)
-			return 1
-		}
-		defer closeDB()
-
-		reader := bufio.NewReader(os.Stdin)
+// cmdRest intentionally defers a thought, making it eligible again after a rest period.
+func cmdRest(args []string) int {
+	var (
+		idArg           string
+		forArg          string
+		untilArg        string
+		note            *string
+		unrecognizedArg string
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--for":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "rest: --for needs a duration")
+				return 2
+			}
+			forArg = args[i+1]
+			i++
+		case "--until":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "rest: --until needs a date")
+				return 2
+			}
+			untilArg = args[i+1]
+			i++
+		case "--note":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "rest: --note needs a value")
+				return 2
+			}
+			n := args[i+1]
+			note = &n
+			i++
+		default:
+			if idArg == "" && !strings.HasPrefix(arg, "--") {
+				idArg = arg
+			} else {
+				unrecognizedArg = arg
+			}
+		}
+	}
+
+	if idArg == "" || unrecognizedArg != "" || (forArg != "" && untilArg != "") {
+		fmt.Fprintln(os.Stderr, "rest: usage: `peony rest <id> [--for duration | --until date] [--note text]`")
+		return 2
+	}
+
+	now := time.Now()
+	var rest *core.RestPeriod
+	if forArg != "" {
+		d, err := core.ParseSpan(forArg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rest: invalid duration: %v\n", err)
+			return 2
+		}
+		restFor := core.RestFor(now, d)
+		rest = &restFor
+	}
+	if untilArg != "" {
+		until, err := core.ParseWhen(untilArg, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "rest: invalid date: %v\n", err)
+			return 2
+		}
+		if !until.After(now) {
+			fmt.Fprintln(os.Stderr, "rest: --until must be in the future")
+			return 2
+		}
+		restUntil := core.RestUntil(until)
+		rest = &restUntil
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rest: %v\n", err)
+		return 1
+	}
+
+	id, err := st.ResolveThoughtRef(idArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rest: %v\n", err)
+		return 2
+	}
+
+	if err := st.RestThought(id, rest, note); err != nil {
+		fmt.Fprintf(os.Stderr, "rest: %v\n", err)
+		return 1
+	}
+
+	thought, _, err := st.GetThought(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rest: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Resting #%d until %s.\n", id, thought.EligibilityAt.Local().Format("2006-01-02 15:04"))
+	return 0
+}
+
+// cmdArchive moves a thought into long-term memory without demand.
+func cmdArchive(args []string) int {
+	var (
+		idArg           string
+		note            *string
+		unrecognizedArg string
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--note":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "archive: --note needs a value")
+				return 2
+			}
+			n := args[i+1]
+			note = &n
+			i++
+		default:
+			if idArg == "" && !strings.HasPrefix(arg, "--") {
+				idArg = arg
+			} else {
+				unrecognizedArg = arg
+			}
+		}
+	}
+
+	if idArg == "" || unrecognizedArg != "" {
+		fmt.Fprintln(os.Stderr, "archive: usage: `peony archive <id> [--note text]`")
+		return 2
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "archive: %v\n", err)
+		return 1
+	}
+
+	id, err := st.ResolveThoughtRef(idArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "archive: %v\n", err)
+		return 2
+	}
+
+	if err := st.ArchiveThought(id, note); err != nil {
+		fmt.Fprintf(os.Stderr, "archive: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Archived #%d.\n", id)
+	return 0
+}
+
+// cmdRevive brings a terminal thought back into the garden to rest again.
+func cmdRevive(args []string) int {
+	var (
+		idArg           string
+		note            *string
+		unrecognizedArg string
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--note":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "revive: --note needs a value")
+				return 2
+			}
+			n := args[i+1]
+			note = &n
+			i++
+		default:
+			if idArg == "" && !strings.HasPrefix(arg, "--") {
+				idArg = arg
+			} else {
+				unrecognizedArg = arg
+			}
+		}
+	}
+
+	if idArg == "" || unrecognizedArg != "" {
+		fmt.Fprintln(os.Stderr, "revive: usage: `peony revive <id> [--note text]`")
+		return 2
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "revive: %v\n", err)
+		return 1
+	}
+
+	id, err := st.ResolveThoughtRef(idArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "revive: %v\n", err)
+		return 2
+	}
+
+	if err := st.ReviveThought(id, note); err != nil {
+		fmt.Fprintf(os.Stderr, "revive: %v\n", err)
+		return 1
+	}
+
+	thought, _, err := st.GetThought(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "revive: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Revived #%d. It will rest until %s.\n", id, thought.EligibilityAt.Local().Format("2006-01-02 15:04"))
+	return 0
+}
+
+// cmdPurge permanently deletes released thoughts (and their event history).
+func cmdPurge(args []string) int {
+	var (
+		idArg           string
+		beforeArg       string
+		unrecognizedArg string
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--before":
+			if i+1 >= len(args) {
+				fmt.Fprintln(os.Stderr, "purge: --before needs a date")
+				return 2
+			}
+			beforeArg = args[i+1]
+			i++
+		default:
+			if idArg == "" && !strings.HasPrefix(arg, "--") {
+				idArg = arg
+			} else {
+				unrecognizedArg = arg
+			}
+		}
+	}
+
+	if unrecognizedArg != "" || (idArg == "") == (beforeArg == "") {
+		fmt.Fprintln(os.Stderr, "purge: usage: `peony purge <id>` or `peony purge --before <date>`")
+		return 2
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "purge: %v\n", err)
+		return 1
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	if beforeArg != "" {
+		cutoff, err := core.ParseWhen(beforeArg, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "purge: invalid date: %v\n", err)
+			return 2
+		}
+
+		ok, err := promptYesNo(reader, fmt.Sprintf("Permanently delete every thought released before %s? This cannot be undone.", cutoff.Format("2006-01-02")))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "purge: %v\n", err)
+			return 1
+		}
+		if !ok {
+			return 0
+		}
+
+		n, err := st.PurgeReleasedBefore(cutoff)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "purge: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Purged %d released thought(s).\n", n)
+		return 0
+	}
+
+	id, err := st.ResolveThoughtRef(idArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "purge: %v\n", err)
+		return 2
+	}
+
+	// Refuse before asking, so nobody confirms a deletion that was never going to happen.
+	thought, _, err := st.GetThought(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "purge: %v\n", err)
+		return 1
+	}
+	if thought.CurrentState != core.StateReleased {
+		fmt.Fprintf(os.Stderr, "purge: #%d is %s; release it first\n", id, thought.CurrentState)
+		return 1
+	}
+
+	ok, err := promptYesNo(reader, fmt.Sprintf("Permanently delete thought #%d and its history? This cannot be undone.", id))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "purge: %v\n", err)
+		return 1
+	}
+	if !ok {
+		return 0
+	}
+
+	if err := st.PurgeThought(id); err != nil {
+		fmt.Fprintf(os.Stderr, "purge: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Purged #%d.\n", id)
+	return 0
+}
+
+// cmdPause freezes ripening until cmdResume is run.
+func cmdPause(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "pause: usage: `peony pause`")
+		return 2
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pause: %v\n", err)
+		return 1
+	}
+
+	if _, err := st.PauseRipening(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	fmt.Println("Peony is paused. Nothing will ripen while you are away; run `peony resume` when you are back.")
+	return 0
+}
+
+// cmdResume ends a pause, moving every waiting thought forward by the time spent away.
+func cmdResume(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "resume: usage: `peony resume`")
+		return 2
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "resume: %v\n", err)
+		return 1
+	}
+
+	interval, shifted, err := st.ResumeRipening()
+	if err != nil {
+		if errors.Is(err, storage.ErrNotPaused) {
+			fmt.Println("Peony is not paused.")
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Welcome back. You were away for %s; %d waiting thought(s) were moved forward by the same amount.\n",
+		core.FormatSpan(interval.Round(time.Minute)), shifted)
+	return 0
+}
+
+// cmdEvolve displays evolved thoughts or marks a thought as evolved.
+func cmdEvolve(args []string) int {
+	if len(args) == 0 {
+		st, err := openStore()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "evolve: %v\n", err)
+			return 1
+		}
+
+		reader := bufio.NewReader(os.Stdin)
 		pageSize := 10
 		page := 0
 
@@ -703,10 +1624,11 @@ func cmdEvolve(args []string) int {
 			}
 
 			fmt.Printf("Page %d\n", page+1)
-			fmt.Printf("%-6s %-10s %-5s %-20s %s\n", "ID", "STATE", "TEND", "UPDATED", "OVERVIEW")
+			fmt.Printf("%-6s %-9s %-10s %-5s %-20s %s\n", "ID", "UID", "STATE", "TEND", "UPDATED", "OVERVIEW")
 			for _, th := range thoughts {
-				fmt.Printf("%-6d %-10s %-5d %-20s %s\n",
+				fmt.Printf("%-6d %-9s %-10s %-5d %-20s %s\n",
 					th.ID,
+					th.UID,
 					th.CurrentState,
 					th.TendCounter,
 					th.UpdatedAt.UTC().Format("2006-01-02 15:04"),
@@ -736,18 +1658,17 @@ func cmdEvolve(args []string) int {
 		}
 	}
 	if len(args) == 1 {
-		id, err := strconv.ParseInt(args[0], 10, 64)
-		if err != nil || id <= 0 {
-			fmt.Fprintln(os.Stderr, "evolve: invalid id")
-			return 2
+		st, err := openStore()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "evolve: %v\n", err)
+			return 1
 		}
 
-		st, closeDB, err := openStore()
+		id, err := st.ResolveThoughtRef(args[0])
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "evolve: %v\n", err)
-			return 1
+			return 2
 		}
-		defer closeDB()
 
 		if err := st.ToEvolve(id); err != nil {
 			fmt.Fprintf(os.Stderr, "evolve: %v\n", err)
@@ -774,13 +1695,29 @@ func cmdHelp(args []string) int {
 Description:
   Captures a new thought and stores it in the captured state.
   The thought will rest for a configured duration before becoming eligible to tend.
+  Use --settle to choose a different duration for this thought only.
+  Valence (-2 heavy to +2 light) and energy (low, medium, high) are optional;
+  interactive capture offers them gently and every change is kept in the history.
+
+  With --edit, the thought is written in your editor, so it can run over
+  many lines; any content given is filled in to start from. The editor file
+  holds valence and energy between two --- lines above the thought. Set
+  addWithEditor in peony config to make this the default, and --no-edit to
+  ask for a single line instead.
+
+  When input is piped and no content is given, everything read from stdin
+  becomes the thought.
 
 Syntax:
-  peony add [content]
+  peony add [--settle duration] [--valence n] [--energy level] [--edit | --no-edit] [content]
   peony a [content]
 
 Examples:
   peony add "I wonder if I should learn Rust"
+  peony add --settle 2w "Should we move next spring?"
+  peony add --valence -2 --energy low "The conversation with my sister"
+  peony add --edit
+  git log -1 | peony add
   peony add
   (prompts interactively if no content provided)
 
@@ -791,10 +1728,17 @@ Examples:
 
 Description:
   View a paginated list of thoughts, a single thought by ID, or filter by state.
-  Without arguments, shows all non-archived thoughts.
+  Without arguments, shows all non-archived thoughts. With --rev, a single
+  thought is shown with its content as it was at that revision.
+  With --as-of, one thought or the whole garden is replayed from its history
+  to show state, tends and eligibility at that moment. A bare date means the
+  end of that day; a date with a time means that moment. The state filters
+  do not take dates: --as-of is the only date view accepts.
 
 Syntax:
   peony view [id]
+  peony view <id> --rev <revision>
+  peony view [id | filter] --as-of <date>
   peony view [--filter | filter]
   peony v [id]
 
@@ -804,6 +1748,9 @@ Filters:
 Examples:
   peony view
   peony view 12
+  peony view 12 --rev 1
+  peony view --as-of 2026-06-01
+  peony view 12 --as-of "3 months ago"
   peony view --archived
   peony view captured
 
@@ -814,32 +1761,256 @@ Examples:
 
 Description:
   Lists thoughts that are eligible to tend, or opens an interactive editor
-  to tend a specific thought by ID.
+  to tend a specific thought by ID. The editor also shows the thought's
+  valence, energy and tags; changing them is recorded in the history.
+
+  The editor file opens with a settings block between two --- lines:
+  valence, energy, tags, next and rest. Set next to rest, evolve, release
+  or archive (and rest to e.g. 2w) to finish the whole tend in one pass;
+  keep saves the edit without tending; blank asks afterwards. Mistakes are
+  reported when the editor closes, with a chance to fix them.
+
+  Only lines starting with # inside the settings block are comments. Below
+  it, the thought and note are kept exactly as written. A line that is
+  "--- note ---" or starts with \ is written with one extra \ in front.
+
+  Nothing is saved until every question is answered, so Ctrl-C leaves the
+  thought as it was. A thought left tended without a next step (for example
+  by an interrupted older version) is offered for resolution first.
+
+  Your edit is kept as a draft until the tend is saved, so an editor crash
+  or a failed save loses nothing; the next tend of that thought offers to
+  restore it (see peony help drafts).
+
+  If the thought is saved from another terminal while the editor is open,
+  peony shows both changes and asks whether to keep yours, keep theirs,
+  or merge the two by hand in the editor.
+
+  With --session, peony walks through every ripe thought one at a time.
+  For each you can tend it, skip it for now, let it rest (the spacing
+  policy picks how long), or stop. A short summary closes the session.
+  If something goes wrong with one thought, it is left as it was and the
+  session moves on; the summary counts it.
 
 Syntax:
   peony tend [id]
+  peony tend --session
   peony t [id]
 
 Examples:
   peony tend
   peony tend 5
+  peony tend --session
+
+`)
+
+	case "rest", "--rest":
+		fmt.Print(`peony rest — intentionally defer a thought
+
+Description:
+  Moves a thought into the resting state. It will not surface for tending
+  until the rest period is over. Without --for or --until, the configured
+  spacing policy picks the period from how often the thought has been
+  tended. The chosen period is kept in the history.
+
+Syntax:
+  peony rest <id> [--for duration | --until date] [--note text]
+
+Examples:
+  peony rest 4
+  peony rest 4 --for 3d
+  peony rest 4 --until 2027-01-15
+  peony rest 4 --until "next monday"
+  peony rest k7m2xq9a --for 36h --note "after the move"
+
+`)
+
+	case "archive", "--archive":
+		fmt.Print(`peony archive — keep a thought in long-term memory
+
+Description:
+  Moves a thought into the archived state. It is preserved with its
+  history but will never surface for tending.
+
+Syntax:
+  peony archive <id> [--note text]
+
+Examples:
+  peony archive 6
+  peony archive 6 --note "good to remember, nothing to do"
+
+`)
+
+	case "revive", "--revive":
+		fmt.Print(`peony revive — bring a thought back into the garden
+
+Description:
+  Moves an evolved, archived or released thought back to resting. It will
+  surface again after the configured settle duration. The history records
+  which state it returned from, with an optional note.
+
+Syntax:
+  peony revive <id> [--note text]
+
+Examples:
+  peony revive 6
+  peony revive k7m2xq9a --note "relevant again after the offer"
 
 `)
 
 	case "release", "--release":
-		fmt.Print(`peony release — remove a thought permanently
+		fmt.Print(`peony release — let a thought go
 
 Description:
-  Permanently deletes a thought and its event history from Peony.
-  This action cannot be undone.
+  Moves a thought into the released state. The thought and its history
+  are kept as a quiet tombstone; an optional closing note is recorded.
+  Use peony purge to delete released thoughts for good.
 
 Syntax:
-  peony release <id>
-  peony r <id>
+  peony release <id> [--note text]
+  peony r <id> [--note text]
 
 Examples:
   peony release 8
-  peony r 3
+  peony r 3 --note "this no longer needs me"
+
+`)
+
+	case "purge", "--purge":
+		fmt.Print(`peony purge — permanently delete released thoughts
+
+Description:
+  Deletes a released thought and its event history from Peony, either one
+  at a time or everything released before a date. Purge is the only way
+  history is ever removed; each purge leaves a note of the thought's UID
+  and how many events went with it.
+  This action cannot be undone.
+
+Syntax:
+  peony purge <id>
+  peony purge --before <date>
+
+Examples:
+  peony purge 8
+  peony purge --before 2026-01-01
+  peony purge --before today
+
+`)
+
+	case "history", "--history":
+		fmt.Print(`peony history — list the saved revisions of a thought
+
+Description:
+  Every edit to a thought's content is kept as a numbered revision, linked
+  to a "revised" event in its history. r1 is the content as captured.
+
+Syntax:
+  peony history <id>
+
+Examples:
+  peony history 5
+  peony view 5 --rev 2
+
+`)
+
+	case "diff", "--diff":
+		fmt.Print(`peony diff — show what changed between two revisions
+
+Description:
+  Shows a word-level diff between two revisions of a thought. Removed words
+  appear as [-word-] and added words as {+word+}. With no revisions the
+  latest edit is shown; with one, that revision is compared to the latest.
+
+Syntax:
+  peony diff <id> [rev] [rev]
+
+Examples:
+  peony diff 5
+  peony diff 5 r1
+  peony diff 5 1 3
+
+`)
+
+	case "pause", "--pause", "resume", "--resume":
+		fmt.Print(`peony pause / peony resume — step away without everything ripening at once
+
+Description:
+  pause records the moment you step away and silences the ready notice.
+  resume moves every captured and resting thought forward by the time you
+  were paused, so nothing ripens while you are gone. Each moved thought
+  keeps a note of the shift in its history.
+
+Syntax:
+  peony pause
+  peony resume
+
+Examples:
+  peony pause
+  peony resume
+
+`)
+
+	case "drafts", "--drafts":
+		fmt.Print(`peony drafts — pick up edits from unfinished tends
+
+Description:
+  While you tend, the editor text is kept as a draft in the drafts folder
+  next to the database, until the tend is saved or you say you are not
+  satisfied. If the editor crashes, the edit cannot be read, or the tend is
+  interrupted, the draft waits for you. Tending that thought again offers
+  to restore it. A draft remembers the version it was written against; if
+  the thought was saved since, you choose which version to keep.
+
+  With no arguments, lists the waiting drafts. With an ID, resumes the tend
+  with the draft in the editor. discard drops a draft for good.
+
+Syntax:
+  peony drafts
+  peony drafts <id>
+  peony drafts discard <id>
+
+Examples:
+  peony drafts
+  peony drafts 5
+  peony drafts discard 5
+
+`)
+
+	case "rebuild", "--rebuild":
+		fmt.Print(`peony rebuild — check thoughts against their history
+
+Description:
+  Every thought keeps a snapshot (state, tend count, last tended, timestamps)
+  next to its append-only history of events. rebuild re-derives the snapshot
+  from the history and reports every field that disagrees. With --repair the
+  snapshots are rewritten to match the history; the history is never changed.
+
+Syntax:
+  peony rebuild [id] [--repair]
+
+Examples:
+  peony rebuild
+  peony rebuild 5
+  peony rebuild --repair
+
+`)
+
+	case "db", "--db":
+		fmt.Print(`peony db status — show where the database stands
+
+Description:
+  Shows the database path, its schema version, every migration step it has
+  been through and when, and any steps still pending. Pending steps run the
+  next time peony opens the database, each in its own transaction; steps
+  that rewrite existing data save a backup next to the database first.
+  Looking never migrates or creates anything. A database written by a newer
+  peony is refused rather than touched.
+
+Syntax:
+  peony db status
+
+Examples:
+  peony db status
 
 `)
 
@@ -865,18 +2036,52 @@ Examples:
 		fmt.Print(`peony config — view and configure defaults
 
 Description:
-  View or update configuration settings like editor and settle duration.
+  View or update configuration settings like editor, settle duration and
+  how rest intervals grow with repeated tending.
 
 Syntax:
   peony config
   peony c
   peony config [--editor | editor]
-  peony config [--settleDuration | settleDuration] 
+  peony config [--settleDuration | settleDuration] [duration]
+  peony config [--spacing | spacing] [fixed | linear | exponential]
+  peony config [--spacingCap | spacingCap] [duration]
+  peony config [--surfaceHours | surfaceHours] [HH:MM-HH:MM,... | none]
+  peony config [--quietHours | quietHours] [HH:MM-HH:MM,... | none]
+  peony config [--quietDays | quietDays] [weekdays | weekends | mon,tue,... | none]
+  peony config [--dailyBudget | dailyBudget] [count | none]
+  peony config [--addWithEditor | addWithEditor] [on | off]
+
+Spacing:
+  fixed          Every rest lasts the settle duration
+  linear         Each tend adds half a settle duration to the next rest
+  exponential    Each tend makes the next rest half again as long
+  Growing policies never rest longer than spacingCap.
+
+Surfacing windows (local time):
+  surfaceHours   Ripe thoughts only surface inside these windows
+  quietHours     Nothing surfaces inside these windows
+  quietDays      Nothing surfaces on these days
+
+Daily budget:
+  dailyBudget    At most this many ripe thoughts are offered each day. The
+                 choice stays the same all day; the rest wait quietly.
+                 Resting or releasing an offered thought does not free its slot.
+
+Capture:
+  addWithEditor  peony add without content opens the editor instead of
+                 asking for a single line (peony add --no-edit overrides).
 
 Examples:
   peony config
   peony config --editor
   peony config settleDuration 24h
+  peony config settleDuration 2w
+  peony config spacing exponential
+  peony config spacingCap 1mo
+  peony config surfaceHours 18:00-22:00 quietDays weekdays
+  peony config dailyBudget 3
+  peony config addWithEditor on
   peony c settleDuration
 
 `)
@@ -904,15 +2109,21 @@ func main() {
 
 	// Print only when the eligible count changes.
 	shouldPrintNotice := cmd != "add" && cmd != "a" && cmd != "tend" && cmd != "t" && cmd != "help" && cmd != "h" && cmd != "version" && cmd != "-v"
-	st, closeDB, err := openStore()
-	if err == nil {
-		defer closeDB()
-		n, err := st.CountTendReady()
+	// db inspects the database as it is, so it must not be opened (and migrated) first.
+	if cmd != "db" {
+		st, err := openStore()
 		if err == nil {
-			if shouldPrintNotice && n > 0 {
-				changed := st.DidCountTendChange(n)
-				if changed {
-					fmt.Fprintf(os.Stderr, "🌱 %d thoughts feel ready for tending. Run: peony tend\n", n)
+			n, err := st.CountTendReady()
+			// The notice stays silent while Peony is paused.
+			if pausedAt, pauseErr := st.PausedSince(); pauseErr != nil || pausedAt != nil {
+				shouldPrintNotice = false
+			}
+			if err == nil {
+				if shouldPrintNotice && n > 0 {
+					changed := st.DidCountTendChange(n)
+					if changed {
+						fmt.Fprintf(os.Stderr, "🌱 %d thoughts feel ready for tending. Run: peony tend\n", n)
+					}
 				}
 			}
 		}
@@ -920,34 +2131,67 @@ func main() {
 
 	switch cmd {
 	case "help", "h":
-		os.Exit(cmdHelp(rest))
+		exit(cmdHelp(rest))
 		return
 
 	case "version", "-v":
 		fmt.Println("Peony " + Version)
-		return
+		exit(0)
 
 	case "add", "a":
-		os.Exit(cmdAdd(rest))
+		exit(cmdAdd(rest))
 
 	case "view", "v":
-		os.Exit(cmdView(rest))
+		exit(cmdView(rest))
 
 	case "tend", "t":
-		os.Exit(cmdTend(rest))
+		exit(cmdTend(rest))
+
+	case "rest":
+		exit(cmdRest(rest))
+
+	case "archive":
+		exit(cmdArchive(rest))
+
+	case "revive":
+		exit(cmdRevive(rest))
 
 	case "release", "r":
-		os.Exit(cmdRelease(rest))
+		exit(cmdRelease(rest))
+
+	case "purge":
+		exit(cmdPurge(rest))
+
+	case "history":
+		exit(cmdHistory(rest))
+
+	case "diff":
+		exit(cmdDiff(rest))
+
+	case "pause":
+		exit(cmdPause(rest))
+
+	case "resume":
+		exit(cmdResume(rest))
+
+	case "rebuild":
+		exit(cmdRebuild(rest))
+
+	case "drafts":
+		exit(cmdDrafts(rest))
+
+	case "db":
+		exit(cmdDB(rest))
 
 	case "evolve", "e":
-		os.Exit(cmdEvolve(rest))
+		exit(cmdEvolve(rest))
 
 	case "configure", "config", "c":
-		os.Exit(cmdConfigure(rest))
+		exit(cmdConfigure(rest))
 
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", cmd)
 		PrintHelp()
-		os.Exit(2)
+		exit(2)
 	}
 }
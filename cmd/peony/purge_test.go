@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+func TestPurgeRefusesAKeptThoughtBeforeAsking(t *testing.T) {
+	st := useTempGarden(t)
+	t.Cleanup(func() {
+		if sharedStore.close != nil {
+			sharedStore.close()
+		}
+		sharedStore.st, sharedStore.close = nil, nil
+	})
+
+	id, _, err := st.CreateThought("still kept", core.RestFor(time.Now(), time.Hour), core.Feeling{})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	// A yes is waiting on stdin; it must still be there afterwards because nothing was asked.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	if _, err := w.WriteString("y\n"); err != nil {
+		t.Fatalf("write stdin: %v", err)
+	}
+	_ = w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		_ = r.Close()
+	})
+
+	if code := cmdPurge([]string{strconv.FormatInt(id, 10)}); code != 1 {
+		t.Errorf("purge of a captured thought exit code = %d, want 1", code)
+	}
+	if unread, _ := io.ReadAll(r); string(unread) != "y\n" {
+		t.Errorf("purge asked before refusing; stdin left %q, want %q", unread, "y\n")
+	}
+	if _, _, err := st.GetThought(id); err != nil {
+		t.Errorf("the kept thought is gone: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// cmdRebuild compares thought snapshots with their event history and, with --repair, fixes them.
+func cmdRebuild(args []string) int {
+	repair := false
+	ref := ""
+	for _, arg := range args {
+		switch {
+		case arg == "--repair":
+			repair = true
+		case ref == "":
+			ref = arg
+		default:
+			fmt.Fprintln(os.Stderr, "rebuild: usage: `peony rebuild [id] [--repair]`")
+			return 2
+		}
+	}
+
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rebuild: %v\n", err)
+		return 1
+	}
+
+	var id int64
+	if ref != "" {
+		if id, err = st.ResolveThoughtRef(ref); err != nil {
+			fmt.Fprintf(os.Stderr, "rebuild: %v\n", err)
+			return 2
+		}
+	}
+
+	reports, err := st.RebuildSnapshots(id, repair)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "rebuild: %v\n", err)
+		return 1
+	}
+
+	drifted, repaired := 0, 0
+	for _, report := range reports {
+		if report.NoHistory {
+			fmt.Printf("#%d (%s) has no history to rebuild from; left as is.\n", report.ThoughtID, report.UID)
+			continue
+		}
+		drifted++
+		fmt.Printf("#%d (%s) disagrees with its history:\n", report.ThoughtID, report.UID)
+		for _, d := range report.Discrepancies {
+			fmt.Printf("  %-15s snapshot %s, history %s\n", d.Field, d.Snapshot, d.Derived)
+		}
+		if report.Repaired {
+			repaired++
+		}
+	}
+
+	switch {
+	case drifted == 0 && id != 0:
+		fmt.Printf("#%d agrees with its history.\n", id)
+	case drifted == 0:
+		fmt.Println("Every thought agrees with its history.")
+	case repair:
+		fmt.Printf("\nRepaired %d thought(s) from their history.\n", repaired)
+	default:
+		fmt.Printf("\n%d thought(s) disagree. Run `peony rebuild --repair` to rewrite them from their history.\n", drifted)
+	}
+	if drifted > 0 && !repair {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+	"github.com/divijg19/peony/internal/storage"
+)
+
+// sessionSummary tallies what happened during a tend session.
+type sessionSummary struct {
+	tended  map[core.State]int
+	edited  int
+	rested  int
+	skipped int
+	// failed counts thoughts passed over because of an error; they are also counted in skipped.
+	failed   int
+	left     int
+	finished bool
+}
+
+// runTendSession walks through every thought that is ready to tend, one at a time.
+// Each thought is saved as soon as it is done, so stopping early keeps earlier work.
+func runTendSession(reader *bufio.Reader) int {
+	st, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tend: %v\n", err)
+		return 1
+	}
+
+	if !core.Windows.Open(time.Now()) {
+		fmt.Printf("Thoughts are resting until a reflection window opens (%s).\n", core.Windows.Describe())
+		return 0
+	}
+
+	ready, err := listReadyThoughts(st)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tend: %v\n", err)
+		return 1
+	}
+	if len(ready) == 0 {
+		fmt.Println("Nothing is waiting for you right now.")
+		return 0
+	}
+
+	guard := guardInterrupts("Session stopped. Thoughts already tended are saved; the current one is unchanged.")
+	defer guard.Stop()
+	reader = guard.reader(reader)
+	summary := sessionSummary{tended: make(map[core.State]int)}
+	inputLost, interrupted := false, false
+
+	fmt.Printf("%d thought(s) ready to tend. Take them one at a time.\n", len(ready))
+	for i, candidate := range ready {
+		if !core.Windows.Open(time.Now()) {
+			fmt.Printf("\nThe reflection window has closed (%s).\n", core.Windows.Describe())
+			summary.left = len(ready) - i
+			break
+		}
+		// Re-read each thought: it may have been tended or rested from another terminal since
+		// the session began, or have left today's selection.
+		thought, _, err := st.GetTendThought(candidate.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "tend: #%d is no longer waiting (%v); passing over it\n", candidate.ID, err)
+			summary.skipped++
+			continue
+		}
+
+		fmt.Println()
+		fmt.Printf("(%d of %d) #%d %s %s (%d tends)\n", i+1, len(ready), thought.ID, thought.UID, thought.CurrentState, thought.TendCounter)
+		fmt.Println(sessionPreview(thought.Content))
+
+		choice, err := promptChoice(reader, "What now?", []string{"tend", "skip", "rest", "stop"})
+		if errors.Is(err, errInterrupted) {
+			summary.left = len(ready) - i
+			interrupted = true
+			break
+		}
+		if err != nil {
+			// Without input nothing more can be asked; close the session with what was done.
+			fmt.Fprintf(os.Stderr, "tend: %v\n", err)
+			summary.left = len(ready) - i
+			inputLost = true
+			break
+		}
+
+		switch choice {
+		case "tend":
+			outcome, err := tendSessionThought(st, reader, guard, thought)
+			if errors.Is(err, errInterrupted) {
+				interrupted = true
+				if !outcome.Saved {
+					// Left mid-tend: the thought is unchanged and waits with the rest.
+					summary.left = len(ready) - i
+					break
+				}
+				err = nil
+			}
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "tend: #%d: %v; it was left as it was\n", thought.ID, err)
+				summary.skipped++
+				summary.failed++
+			case outcome.Tended:
+				summary.tended[outcome.Next]++
+			case outcome.Saved:
+				summary.edited++
+			default:
+				summary.skipped++
+			}
+		case "skip":
+			summary.skipped++
+		case "rest":
+			err := guard.run(interruptDefer, func() error {
+				return st.RestThought(thought.ID, nil, nil)
+			})
+			if errors.Is(err, errInterrupted) {
+				interrupted = true
+				err = nil
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "tend: #%d: %v; it was left as it was\n", thought.ID, err)
+				summary.skipped++
+				summary.failed++
+				continue
+			}
+			summary.rested++
+		case "stop":
+			summary.left = len(ready) - i
+		}
+		if interrupted && summary.left == 0 {
+			summary.left = len(ready) - i - 1
+		}
+		if summary.left > 0 || interrupted {
+			break
+		}
+	}
+	summary.finished = summary.left == 0
+
+	if interrupted {
+		guard.report()
+	}
+	fmt.Println()
+	fmt.Println(summary.String())
+	if interrupted {
+		return 130
+	}
+	if inputLost || summary.failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// tendSessionThought offers any kept draft for thought, then tends it.
+func tendSessionThought(st *storage.Store, reader *bufio.Reader, guard *interruptGuard, thought core.Thought) (tendOutcome, error) {
+	draft, err := offerDraft(reader, thought)
+	if err != nil {
+		return tendOutcome{}, err
+	}
+	return tendThought(st, reader, guard, thought, draft)
+}
+
+// listReadyThoughts collects every thought currently eligible to tend, oldest eligibility first.
+func listReadyThoughts(st *storage.Store) ([]core.Thought, error) {
+	const pageSize = 50
+	ready := make([]core.Thought, 0)
+	for offset := 0; ; offset += pageSize {
+		page, err := st.ListTendThoughtsByPagination(pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		ready = append(ready, page...)
+		if len(page) < pageSize {
+			return ready, nil
+		}
+	}
+}
+
+// sessionPreview shows the first few lines of a thought, indented.
+func sessionPreview(content string) string {
+	const maxLines = 5
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines], "…")
+	}
+	return "  " + strings.Join(lines, "\n  ")
+}
+
+// String renders the summary as a short, gentle closing note.
+func (s sessionSummary) String() string {
+	parts := make([]string, 0, 4)
+
+	tended := 0
+	for _, n := range s.tended {
+		tended += n
+	}
+	if tended > 0 {
+		outcomes := make([]string, 0, len(s.tended))
+		for _, state := range []core.State{core.StateResting, core.StateEvolved, core.StateReleased, core.StateArchived} {
+			if n := s.tended[state]; n > 0 {
+				outcomes = append(outcomes, fmt.Sprintf("%d %s", n, state))
+			}
+		}
+		parts = append(parts, fmt.Sprintf("tended %d (%s)", tended, strings.Join(outcomes, ", ")))
+	}
+	if s.edited > 0 {
+		parts = append(parts, fmt.Sprintf("revisited %d without marking", s.edited))
+	}
+	if s.rested > 0 {
+		parts = append(parts, fmt.Sprintf("let %d rest", s.rested))
+	}
+	if s.skipped > 0 {
+		parts = append(parts, fmt.Sprintf("passed over %d for now", s.skipped))
+	}
+	if s.failed > 0 {
+		parts = append(parts, fmt.Sprintf("could not finish %d because of an error (left as they were)", s.failed))
+	}
+
+	var b strings.Builder
+	if len(parts) == 0 {
+		b.WriteString("Nothing changed, and that is fine too.")
+	} else {
+		b.WriteString("You " + strings.Join(parts, ", ") + ".")
+	}
+	if s.finished {
+		b.WriteString(" That's everything for now.")
+	} else if s.left > 0 {
+		fmt.Fprintf(&b, " %d thought(s) will wait for next time.", s.left)
+	}
+	return b.String()
+}
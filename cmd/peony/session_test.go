@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+func TestSessionSummaryString(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary sessionSummary
+		want    string
+	}{
+		{
+			name:    "nothing done",
+			summary: sessionSummary{finished: true},
+			want:    "Nothing changed, and that is fine too. That's everything for now.",
+		},
+		{
+			name: "tended and rested",
+			summary: sessionSummary{
+				tended:   map[core.State]int{core.StateResting: 2, core.StateReleased: 1},
+				rested:   1,
+				finished: true,
+			},
+			want: "You tended 3 (2 resting, 1 released), let 1 rest. That's everything for now.",
+		},
+		{
+			name:    "stopped early",
+			summary: sessionSummary{edited: 1, skipped: 1, left: 3},
+			want:    "You revisited 1 without marking, passed over 1 for now. 3 thought(s) will wait for next time.",
+		},
+		{
+			name:    "failures are counted and the session still closes",
+			summary: sessionSummary{skipped: 2, failed: 1, finished: true},
+			want:    "You passed over 2 for now, could not finish 1 because of an error (left as they were). That's everything for now.",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.summary.String(); got != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSessionCarriesOnAfterAFailure(t *testing.T) {
+	st := useTempGarden(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("VISUAL", "")
+	// An editor that always fails, so tending the first thought errors out.
+	t.Setenv("EDITOR", "false")
+	t.Cleanup(func() {
+		if sharedStore.close != nil {
+			sharedStore.close()
+		}
+		sharedStore.st, sharedStore.close = nil, nil
+	})
+
+	ids := make([]int64, 0, 2)
+	for i, content := range []string{"first", "second"} {
+		ripeAt := time.Now().Add(time.Duration(i-3) * time.Hour)
+		id, _, err := st.CreateThought(content, core.RestUntil(ripeAt), core.Feeling{})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	code := runTendSession(bufio.NewReader(strings.NewReader("tend\nrest\n")))
+	if code != 1 {
+		t.Errorf("session exit code = %d, want 1 after a failure", code)
+	}
+
+	first, _, err := st.GetThought(ids[0])
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if first.CurrentState != core.StateCaptured || first.Content != "first" {
+		t.Errorf("failed thought = %s %q, want it left as it was", first.CurrentState, first.Content)
+	}
+	second, _, err := st.GetThought(ids[1])
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if second.CurrentState != core.StateResting {
+		t.Errorf("second thought is %s, want the session to have carried on and rested it", second.CurrentState)
+	}
+}
+
+// hookedReader runs hook before the first read, then serves input.
+type hookedReader struct {
+	hook  func()
+	input *strings.Reader
+}
+
+func (r *hookedReader) Read(p []byte) (int, error) {
+	if r.hook != nil {
+		r.hook()
+		r.hook = nil
+	}
+	return r.input.Read(p)
+}
+
+func TestSessionPassesOverAThoughtRestedElsewhere(t *testing.T) {
+	st := useTempGarden(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() {
+		if sharedStore.close != nil {
+			sharedStore.close()
+		}
+		sharedStore.st, sharedStore.close = nil, nil
+	})
+
+	ids := make([]int64, 0, 3)
+	for i, content := range []string{"first", "second", "third"} {
+		ripeAt := time.Now().Add(time.Duration(i-4) * time.Hour)
+		id, _, err := st.CreateThought(content, core.RestUntil(ripeAt), core.Feeling{})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// While the session shows the first thought, another terminal rests the second.
+	reader := &hookedReader{
+		hook: func() {
+			if err := st.RestThought(ids[1], nil, nil); err != nil {
+				t.Errorf("rest elsewhere: %v", err)
+			}
+		},
+		input: strings.NewReader("skip\nrest\n"),
+	}
+	if code := runTendSession(bufio.NewReader(reader)); code != 0 {
+		t.Errorf("session exit code = %d, want 0", code)
+	}
+
+	third, _, err := st.GetThought(ids[2])
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if third.CurrentState != core.StateResting {
+		t.Errorf("third thought is %s, want the session to have reached and rested it", third.CurrentState)
+	}
+	second, events, err := st.GetThought(ids[1])
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	rests := 0
+	for _, e := range events {
+		if e.NextState != nil && *e.NextState == core.StateResting {
+			rests++
+		}
+	}
+	if second.CurrentState != core.StateResting || rests != 1 {
+		t.Errorf("second thought is %s with %d rests, want the rest made elsewhere only", second.CurrentState, rests)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/divijg19/peony/internal/core"
 )
 
 // DefaultSettleDuration is the default rest duration before a thought becomes eligible.
@@ -16,12 +19,21 @@ const DefaultSettleDuration = 18 * time.Hour
 type Config struct {
 	Editor         string `json:"editor,omitempty"`
 	SettleDuration string `json:"settleDuration,omitempty"`
+	Spacing        string `json:"spacing,omitempty"`
+	SpacingCap     string `json:"spacingCap,omitempty"`
+	SurfaceHours   string `json:"surfaceHours,omitempty"`
+	QuietHours     string `json:"quietHours,omitempty"`
+	QuietDays      string `json:"quietDays,omitempty"`
+	DailyBudget    int    `json:"dailyBudget,omitempty"`
+	AddWithEditor  bool   `json:"addWithEditor,omitempty"`
 }
 
 // Default returns the default configuration.
 func Default() Config {
 	return Config{
-		SettleDuration: DefaultSettleDuration.String(),
+		SettleDuration: core.FormatSpan(DefaultSettleDuration),
+		Spacing:        string(core.SpacingFixed),
+		SpacingCap:     core.FormatSpan(core.DefaultSpacingCap),
 	}
 }
 
@@ -87,25 +99,107 @@ func Save(cfg Config) error {
 }
 
 // Normalize ensures defaults are set and invalid values are sanitized.
+// Durations are rewritten in the compact form produced by core.FormatSpan (e.g. 336h becomes 2w).
 func Normalize(cfg Config) Config {
 	cfg.Editor = strings.TrimSpace(cfg.Editor)
-	cfg.SettleDuration = strings.TrimSpace(cfg.SettleDuration)
-	if cfg.SettleDuration == "" {
-		cfg.SettleDuration = DefaultSettleDuration.String()
-		return cfg
+	cfg.SettleDuration = normalizeSpan(cfg.SettleDuration, DefaultSettleDuration)
+	cfg.SpacingCap = normalizeSpan(cfg.SpacingCap, core.DefaultSpacingCap)
+
+	spacing, err := core.ParseSpacing(cfg.Spacing)
+	if err != nil {
+		spacing = core.SpacingFixed
+	}
+	cfg.Spacing = string(spacing)
+
+	if windows, err := core.ParseWindows(cfg.SurfaceHours); err == nil {
+		cfg.SurfaceHours = core.FormatWindows(windows)
+	} else {
+		cfg.SurfaceHours = ""
+	}
+	if windows, err := core.ParseWindows(cfg.QuietHours); err == nil {
+		cfg.QuietHours = core.FormatWindows(windows)
+	} else {
+		cfg.QuietHours = ""
 	}
-	if _, err := time.ParseDuration(cfg.SettleDuration); err != nil {
-		cfg.SettleDuration = DefaultSettleDuration.String()
+	if days, err := core.ParseWeekdays(cfg.QuietDays); err == nil {
+		cfg.QuietDays = core.FormatWeekdays(days)
+	} else {
+		cfg.QuietDays = ""
+	}
+	if cfg.DailyBudget < 0 {
+		cfg.DailyBudget = 0
 	}
 	return cfg
 }
 
+// normalizeSpan returns value in core.FormatSpan form, or fallback when value is empty or invalid.
+func normalizeSpan(value string, fallback time.Duration) string {
+	d, err := core.ParseSpan(strings.TrimSpace(value))
+	if err != nil {
+		return core.FormatSpan(fallback)
+	}
+	return core.FormatSpan(d)
+}
+
 // SettleDuration returns a parsed duration, falling back to DefaultSettleDuration.
 func SettleDuration(cfg Config) time.Duration {
 	cfg = Normalize(cfg)
-	d, err := time.ParseDuration(cfg.SettleDuration)
+	d, err := core.ParseSpan(cfg.SettleDuration)
 	if err != nil {
 		return DefaultSettleDuration
 	}
 	return d
 }
+
+// SpacingPolicy returns the configured resurfacing policy, falling back to fixed spacing.
+func SpacingPolicy(cfg Config) core.SpacingPolicy {
+	cfg = Normalize(cfg)
+	spacing, err := core.ParseSpacing(cfg.Spacing)
+	if err != nil {
+		spacing = core.SpacingFixed
+	}
+	limit, err := core.ParseSpan(cfg.SpacingCap)
+	if err != nil {
+		limit = core.DefaultSpacingCap
+	}
+	return core.SpacingPolicy{Spacing: spacing, Cap: limit}
+}
+
+// Windows returns the configured surfacing windows. Invalid entries are ignored.
+func Windows(cfg Config) core.SurfacingWindows {
+	cfg = Normalize(cfg)
+	reflect, _ := core.ParseWindows(cfg.SurfaceHours)
+	quiet, _ := core.ParseWindows(cfg.QuietHours)
+	days, _ := core.ParseWeekdays(cfg.QuietDays)
+	return core.SurfacingWindows{Reflect: reflect, Quiet: quiet, QuietDays: days}
+}
+
+// DailyBudget returns how many ripe thoughts may be offered per day; zero means no limit.
+func DailyBudget(cfg Config) int {
+	return Normalize(cfg).DailyBudget
+}
+
+// ParseDailyBudget parses a daily budget: a non-negative count, or "none"/"off" for no limit.
+func ParseDailyBudget(s string) (int, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	switch text {
+	case "none", "off", "unlimited":
+		return 0, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid daily budget %q (want a number, or none)", s)
+	}
+	return n, nil
+}
+
+// ParseSwitch parses an on/off setting such as "on", "off", "yes", "no", "true" or "false".
+func ParseSwitch(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "on", "yes", "true", "1":
+		return true, nil
+	case "off", "no", "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid switch %q (want on or off)", s)
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"hash/fnv"
+	"sort"
+	"time"
+)
+
+// DailyBudget limits how many ripe thoughts are offered per day; zero means no limit.
+// It can be overridden via configuration.
+var DailyBudget int
+
+// SelectForDay picks which ripe thoughts are offered on the local day containing now, given
+// how many thoughts have already been tended that day. Thoughts that were ripe when
+// the day began come first, then ties are broken by a hash of the day and the thought's UID,
+// so the selection stays stable for the whole day and the rest wait quietly.
+func SelectForDay(ripe []Thought, now time.Time, budget, used int) []Thought {
+	if budget <= 0 {
+		return ripe
+	}
+	remaining := budget - used
+	if remaining <= 0 {
+		return []Thought{}
+	}
+
+	local := now.Local()
+	dayStart := startOfDay(local)
+	dayKey := local.Format("2006-01-02")
+
+	type ranked struct {
+		thought  Thought
+		newToday bool
+		hash     uint64
+	}
+	candidates := make([]ranked, 0, len(ripe))
+	for _, t := range ripe {
+		h := fnv.New64a()
+		h.Write([]byte(dayKey + "/" + t.UID))
+		candidates = append(candidates, ranked{
+			thought:  t,
+			newToday: !t.EligibilityAt.Before(dayStart),
+			hash:     h.Sum64(),
+		})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].newToday != candidates[j].newToday {
+			return !candidates[i].newToday
+		}
+		return candidates[i].hash < candidates[j].hash
+	})
+
+	if len(candidates) > remaining {
+		candidates = candidates[:remaining]
+	}
+	selected := make([]Thought, 0, len(candidates))
+	for _, c := range candidates {
+		selected = append(selected, c.thought)
+	}
+	return selected
+}
+
+// DaySelection records which thoughts have taken a slot of the daily budget on one local day.
+// A thought keeps its slot for the rest of the day even once it is rested or released, so
+// clearing thoughts away does not let others in.
+type DaySelection struct {
+	Day     string   `json:"day"`
+	Budget  int      `json:"budget"`
+	Offered []string `json:"offered"`
+}
+
+// PinForDay brings sel up to date for the local day containing now. A selection from another day
+// or made under another budget starts afresh from the UIDs already tended that day; free slots
+// are then filled from ripe with SelectForDay. It reports whether sel changed.
+func PinForDay(sel DaySelection, ripe []Thought, tendedToday []string, now time.Time, budget int) (DaySelection, bool) {
+	day := now.Local().Format("2006-01-02")
+	changed := false
+	if sel.Day != day || sel.Budget != budget {
+		sel = DaySelection{Day: day, Budget: budget, Offered: make([]string, 0, budget)}
+		for _, uid := range tendedToday {
+			if !sel.Includes(uid) {
+				sel.Offered = append(sel.Offered, uid)
+			}
+		}
+		changed = true
+	}
+
+	candidates := make([]Thought, 0, len(ripe))
+	for _, t := range ripe {
+		if !sel.Includes(t.UID) {
+			candidates = append(candidates, t)
+		}
+	}
+	for _, t := range SelectForDay(candidates, now, budget, len(sel.Offered)) {
+		sel.Offered = append(sel.Offered, t.UID)
+		changed = true
+	}
+	return sel, changed
+}
+
+// Includes reports whether the thought with uid has a slot in the selection.
+func (sel DaySelection) Includes(uid string) bool {
+	for _, offered := range sel.Offered {
+		if offered == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+// ripeThoughts returns n thoughts with UIDs t0..tn-1 that became ripe at eligibleAt.
+func ripeThoughts(n int, eligibleAt time.Time) []Thought {
+	thoughts := make([]Thought, 0, n)
+	for i := 0; i < n; i++ {
+		thoughts = append(thoughts, Thought{ID: int64(i + 1), UID: fmt.Sprintf("t%d", i), EligibilityAt: eligibleAt})
+	}
+	return thoughts
+}
+
+func uids(thoughts []Thought) []string {
+	out := make([]string, 0, len(thoughts))
+	for _, t := range thoughts {
+		out = append(out, t.UID)
+	}
+	return out
+}
+
+func TestSelectForDay(t *testing.T) {
+	morning := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.Local)
+	yesterday := morning.Add(-24 * time.Hour)
+	ripe := ripeThoughts(6, yesterday)
+
+	tests := []struct {
+		name         string
+		budget, used int
+		want         int
+	}{
+		{"no budget offers all", 0, 0, 6},
+		{"budget limits", 3, 0, 3},
+		{"used slots count", 3, 2, 1},
+		{"budget spent", 3, 3, 0},
+		{"overspent", 3, 5, 0},
+		{"budget above ripe", 10, 0, 6},
+	}
+	for _, tt := range tests {
+		if got := SelectForDay(ripe, morning, tt.budget, tt.used); len(got) != tt.want {
+			t.Errorf("%s: SelectForDay picked %d, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
+
+func TestSelectForDayIsStableWithinADay(t *testing.T) {
+	morning := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.Local)
+	ripe := ripeThoughts(20, morning.Add(-48*time.Hour))
+
+	first := uids(SelectForDay(ripe, morning, 5, 0))
+	evening := uids(SelectForDay(ripe, morning.Add(12*time.Hour), 5, 0))
+	if !slices.Equal(first, evening) {
+		t.Errorf("selection changed within the day: %v then %v", first, evening)
+	}
+
+	reversed := slices.Clone(ripe)
+	slices.Reverse(reversed)
+	if got := uids(SelectForDay(reversed, morning, 5, 0)); !slices.Equal(first, got) {
+		t.Errorf("selection depends on input order: %v then %v", first, got)
+	}
+}
+
+func TestSelectForDayPrefersThoughtsRipeAtDawn(t *testing.T) {
+	morning := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.Local)
+	ripe := append(ripeThoughts(4, morning.Add(-time.Hour)), Thought{ID: 9, UID: "old", EligibilityAt: morning.Add(-48 * time.Hour)})
+
+	got := uids(SelectForDay(ripe, morning, 1, 0))
+	if !slices.Equal(got, []string{"old"}) {
+		t.Errorf("SelectForDay = %v, want the thought ripe before the day began", got)
+	}
+}
+
+func TestPinForDay(t *testing.T) {
+	morning := time.Date(2026, time.June, 1, 9, 0, 0, 0, time.Local)
+	ripe := ripeThoughts(6, morning.Add(-48*time.Hour))
+
+	sel, changed := PinForDay(DaySelection{}, ripe, nil, morning, 3)
+	if !changed || sel.Day != "2026-06-01" || sel.Budget != 3 || len(sel.Offered) != 3 {
+		t.Fatalf("PinForDay from empty = %+v, changed %v", sel, changed)
+	}
+
+	// Thoughts leaving the ripe list keep their slots, so nothing new is let in.
+	remaining := make([]Thought, 0, len(ripe))
+	for _, th := range ripe {
+		if !sel.Includes(th.UID) {
+			remaining = append(remaining, th)
+		}
+	}
+	again, changed := PinForDay(sel, remaining, nil, morning.Add(6*time.Hour), 3)
+	if changed || !slices.Equal(again.Offered, sel.Offered) {
+		t.Errorf("PinForDay later the same day = %+v, changed %v; want %+v unchanged", again, changed, sel)
+	}
+
+	// A new day starts afresh.
+	tomorrow, changed := PinForDay(sel, ripe, nil, morning.Add(24*time.Hour), 3)
+	if !changed || tomorrow.Day != "2026-06-02" || len(tomorrow.Offered) != 3 {
+		t.Errorf("PinForDay the next day = %+v, changed %v", tomorrow, changed)
+	}
+
+	// A changed budget starts afresh too, counting what was already tended today.
+	rebudgeted, changed := PinForDay(sel, ripe, []string{"done"}, morning, 2)
+	if !changed || rebudgeted.Budget != 2 || !rebudgeted.Includes("done") || len(rebudgeted.Offered) != 2 {
+		t.Errorf("PinForDay with a new budget = %+v, changed %v", rebudgeted, changed)
+	}
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DiffOp says whether a chunk of a word diff is shared, added or removed.
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffInsert
+	DiffDelete
+)
+
+// DiffChunk is a run of text that a word diff kept, added or removed.
+type DiffChunk struct {
+	Op   DiffOp
+	Text string
+}
+
+// maxDiffCells caps the table DiffWords builds for the part of a text that changed. Beyond it
+// the changed part is shown as one removal and one insertion rather than word by word.
+const maxDiffCells = 1 << 20
+
+// DiffWords compares before and after word by word. Whitespace is kept with the word before it,
+// so joining the equal and deleted chunks gives before, and joining the equal and inserted chunks
+// gives after.
+func DiffWords(before, after string) []DiffChunk {
+	a := wordTokens(before)
+	b := wordTokens(after)
+
+	chunks := make([]DiffChunk, 0)
+	emit := func(op DiffOp, text string) {
+		if n := len(chunks); n > 0 && chunks[n-1].Op == op {
+			chunks[n-1].Text += text
+			return
+		}
+		chunks = append(chunks, DiffChunk{Op: op, Text: text})
+	}
+
+	// Words shared at either end are equal however the middle changed, so only the middle is compared.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && sameWord(a[prefix], b[prefix]) {
+		emit(DiffEqual, b[prefix])
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && sameWord(a[len(a)-1-suffix], b[len(b)-1-suffix]) {
+		suffix++
+	}
+	a, tailB := a[prefix:len(a)-suffix], b[len(b)-suffix:]
+	b = b[prefix : len(b)-suffix]
+
+	if len(a) > 0 && len(b) > 0 && len(a)*len(b) > maxDiffCells {
+		emit(DiffDelete, strings.Join(a, ""))
+		emit(DiffInsert, strings.Join(b, ""))
+		a, b = nil, nil
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if sameWord(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case sameWord(a[i], b[j]):
+			emit(DiffEqual, b[j])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			emit(DiffDelete, a[i])
+			i++
+		default:
+			emit(DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		emit(DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		emit(DiffInsert, b[j])
+	}
+	for _, tok := range tailB {
+		emit(DiffEqual, tok)
+	}
+	return chunks
+}
+
+// DiffStats counts the words added and removed by a diff.
+func DiffStats(chunks []DiffChunk) (added, removed int) {
+	for _, c := range chunks {
+		switch c.Op {
+		case DiffInsert:
+			added += len(strings.Fields(c.Text))
+		case DiffDelete:
+			removed += len(strings.Fields(c.Text))
+		}
+	}
+	return added, removed
+}
+
+// wordTokens splits s into words, each carrying the whitespace that follows it.
+// Leading whitespace becomes a token of its own.
+func wordTokens(s string) []string {
+	tokens := make([]string, 0)
+	start := 0
+	inSpace := true
+	for idx, r := range s {
+		space := unicode.IsSpace(r)
+		if !space && inSpace && idx > start {
+			tokens = append(tokens, s[start:idx])
+			start = idx
+		}
+		inSpace = space
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+// sameWord compares tokens ignoring the whitespace that follows them.
+func sameWord(a, b string) bool {
+	return strings.TrimRightFunc(a, unicode.IsSpace) == strings.TrimRightFunc(b, unicode.IsSpace)
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// joined rebuilds one side of a diff: the equal chunks plus those with op.
+func joined(chunks []DiffChunk, op DiffOp) string {
+	var b strings.Builder
+	for _, c := range chunks {
+		if c.Op == DiffEqual || c.Op == op {
+			b.WriteString(c.Text)
+		}
+	}
+	return b.String()
+}
+
+func TestDiffWords(t *testing.T) {
+	tests := []struct {
+		before, after string
+		want          []DiffChunk
+	}{
+		{"same words", "same words", []DiffChunk{{DiffEqual, "same words"}}},
+		{"", "new", []DiffChunk{{DiffInsert, "new"}}},
+		{"old", "", []DiffChunk{{DiffDelete, "old"}}},
+		{"a quiet cabin in the woods", "a cabin by the woods", []DiffChunk{
+			{DiffEqual, "a "}, {DiffDelete, "quiet "}, {DiffEqual, "cabin "}, {DiffDelete, "in "}, {DiffInsert, "by "}, {DiffEqual, "the woods"},
+		}},
+		{"keep the start, change the end", "keep the start, swap the end", []DiffChunk{
+			{DiffEqual, "keep the start, "}, {DiffDelete, "change "}, {DiffInsert, "swap "}, {DiffEqual, "the end"},
+		}},
+	}
+	for _, tt := range tests {
+		got := DiffWords(tt.before, tt.after)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("DiffWords(%q, %q) = %v, want %v", tt.before, tt.after, got, tt.want)
+		}
+		if joined(got, DiffDelete) != tt.before || joined(got, DiffInsert) != tt.after {
+			t.Errorf("DiffWords(%q, %q) does not rebuild both sides: %v", tt.before, tt.after, got)
+		}
+	}
+}
+
+func TestDiffWordsReplacesLargeChangesWhole(t *testing.T) {
+	before := "start " + strings.Repeat("old ", 1500) + "end"
+	after := "start " + strings.Repeat("new ", 1500) + "end"
+
+	got := DiffWords(before, after)
+	want := []DiffChunk{
+		{DiffEqual, "start "},
+		{DiffDelete, strings.Repeat("old ", 1500)},
+		{DiffInsert, strings.Repeat("new ", 1500)},
+		{DiffEqual, "end"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("DiffWords on a large change gave %d chunks, want one removal and one insertion between the shared ends", len(got))
+	}
+	if added, removed := DiffStats(got); added != 1500 || removed != 1500 {
+		t.Errorf("DiffStats = +%d -%d, want +1500 -1500", added, removed)
+	}
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Valence runs from MinValence (heavy) to MaxValence (light); zero is neutral.
+const (
+	MinValence = -2
+	MaxValence = 2
+)
+
+// Energy levels describe how much a thought asks of you.
+const (
+	EnergyLow    = 1
+	EnergyMedium = 2
+	EnergyHigh   = 3
+)
+
+// Feeling is the optional emotional weight of a thought. Nil fields are unset.
+type Feeling struct {
+	Valence *int
+	Energy  *int
+}
+
+// IsZero reports whether neither valence nor energy is set.
+func (f Feeling) IsZero() bool {
+	return f.Valence == nil && f.Energy == nil
+}
+
+// Equal reports whether two feelings hold the same values.
+func (f Feeling) Equal(other Feeling) bool {
+	return sameInt(f.Valence, other.Valence) && sameInt(f.Energy, other.Energy)
+}
+
+// DescribeChange summarises how a feeling moved from prev to f, e.g. "valence 0 → -2, energy set to low".
+func (f Feeling) DescribeChange(prev Feeling) string {
+	parts := make([]string, 0, 2)
+	if !sameInt(prev.Valence, f.Valence) {
+		parts = append(parts, describeField("valence", prev.Valence, f.Valence, FormatValence))
+	}
+	if !sameInt(prev.Energy, f.Energy) {
+		parts = append(parts, describeField("energy", prev.Energy, f.Energy, EnergyLabel))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseValence parses a valence between MinValence and MaxValence, e.g. "-2", "+1" or "0".
+func ParseValence(s string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || v < MinValence || v > MaxValence {
+		return 0, fmt.Errorf("invalid valence %q (want %d to +%d)", s, MinValence, MaxValence)
+	}
+	return v, nil
+}
+
+// FormatValence renders a valence with an explicit sign, e.g. +1, 0, -2.
+func FormatValence(v int) string {
+	if v > 0 {
+		return fmt.Sprintf("+%d", v)
+	}
+	return strconv.Itoa(v)
+}
+
+// ParseEnergy parses an energy level by name (low, medium, high) or number (1-3).
+func ParseEnergy(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low", "l", "1":
+		return EnergyLow, nil
+	case "medium", "med", "m", "2":
+		return EnergyMedium, nil
+	case "high", "h", "3":
+		return EnergyHigh, nil
+	default:
+		return 0, fmt.Errorf("invalid energy %q (want low, medium or high)", s)
+	}
+}
+
+// EnergyLabel names an energy level, falling back to the number for unknown values.
+func EnergyLabel(e int) string {
+	switch e {
+	case EnergyLow:
+		return "low"
+	case EnergyMedium:
+		return "medium"
+	case EnergyHigh:
+		return "high"
+	default:
+		return strconv.Itoa(e)
+	}
+}
+
+func describeField(name string, prev, next *int, format func(int) string) string {
+	switch {
+	case prev == nil:
+		return fmt.Sprintf("%s set to %s", name, format(*next))
+	case next == nil:
+		return fmt.Sprintf("%s cleared (was %s)", name, format(*prev))
+	default:
+		return fmt.Sprintf("%s %s → %s", name, format(*prev), format(*next))
+	}
+}
+
+func sameInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Event kinds recorded in the events table.
+const (
+	EventCaptured    = "captured"
+	EventStateChange = "state_change"
+	EventRevived     = "revived"
+	// EventShifted records eligibility moved forward after a pause; the state is unchanged.
+	EventShifted = "shifted"
+	// EventRevised records a content edit; the new content is kept as a revision.
+	EventRevised = "revised"
+	// EventTagged is recorded whenever tags are added to or removed from a thought.
+	EventTagged = "tagged"
+	// EventFeeling is recorded whenever a thought's valence or energy changes.
+	EventFeeling = "feeling"
+)
+
+// Transition describes one allowed lifecycle move and the event kind it records.
+type Transition struct {
+	From State
+	To   State
+	Kind string
+}
+
+// transitions is the single source of truth for how a thought may move through its lifecycle.
+var transitions = []Transition{
+	{From: StateCaptured, To: StateTended, Kind: EventStateChange},
+	{From: StateResting, To: StateTended, Kind: EventStateChange},
+
+	{From: StateTended, To: StateResting, Kind: EventStateChange},
+	{From: StateTended, To: StateEvolved, Kind: EventStateChange},
+	{From: StateTended, To: StateReleased, Kind: EventStateChange},
+	{From: StateTended, To: StateArchived, Kind: EventStateChange},
+
+	{From: StateCaptured, To: StateResting, Kind: EventStateChange},
+	{From: StateResting, To: StateResting, Kind: EventStateChange},
+
+	{From: StateCaptured, To: StateArchived, Kind: EventStateChange},
+	{From: StateResting, To: StateArchived, Kind: EventStateChange},
+
+	{From: StateCaptured, To: StateEvolved, Kind: EventStateChange},
+	{From: StateResting, To: StateEvolved, Kind: EventStateChange},
+
+	{From: StateCaptured, To: StateReleased, Kind: EventStateChange},
+	{From: StateResting, To: StateReleased, Kind: EventStateChange},
+	{From: StateEvolved, To: StateReleased, Kind: EventStateChange},
+	{From: StateArchived, To: StateReleased, Kind: EventStateChange},
+
+	{From: StateEvolved, To: StateResting, Kind: EventRevived},
+	{From: StateArchived, To: StateResting, Kind: EventRevived},
+	{From: StateReleased, To: StateResting, Kind: EventRevived},
+}
+
+// ErrInvalidTransition is matched by every TransitionError via errors.Is.
+var ErrInvalidTransition = errors.New("invalid lifecycle transition")
+
+// TransitionError reports a lifecycle move that the transition table does not allow.
+type TransitionError struct {
+	From State
+	To   State
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot move thought from %s to %s", e.From, e.To)
+}
+
+// Is lets callers match any TransitionError with errors.Is(err, ErrInvalidTransition).
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// CheckTransition returns the transition for from→to, or a *TransitionError if the move is not allowed.
+func CheckTransition(from, to State) (Transition, error) {
+	for _, t := range transitions {
+		if t.From == from && t.To == to {
+			return t, nil
+		}
+	}
+	return Transition{}, &TransitionError{From: from, To: to}
+}
+
+// NextStates returns the states a thought in from may move to, in table order.
+func NextStates(from State) []State {
+	next := make([]State, 0)
+	for _, t := range transitions {
+		if t.From == from {
+			next = append(next, t.To)
+		}
+	}
+	return next
+}
+
+// IsTerminal reports whether a state ends the active lifecycle of a thought.
+func IsTerminal(state State) bool {
+	switch state {
+	case StateEvolved, StateReleased, StateArchived:
+		return true
+	default:
+		return false
+	}
+}
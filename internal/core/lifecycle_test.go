@@ -0,0 +1,90 @@
+package core
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		from, to State
+		kind     string
+		ok       bool
+	}{
+		{StateCaptured, StateTended, EventStateChange, true},
+		{StateResting, StateTended, EventStateChange, true},
+		{StateTended, StateResting, EventStateChange, true},
+		{StateTended, StateReleased, EventStateChange, true},
+		{StateCaptured, StateResting, EventStateChange, true},
+		{StateResting, StateResting, EventStateChange, true},
+		{StateArchived, StateReleased, EventStateChange, true},
+		{StateEvolved, StateResting, EventRevived, true},
+		{StateArchived, StateResting, EventRevived, true},
+		{StateReleased, StateResting, EventRevived, true},
+
+		{StateTended, StateTended, "", false},
+		{StateTended, StateCaptured, "", false},
+		{StateReleased, StateTended, "", false},
+		{StateReleased, StateArchived, "", false},
+		{StateEvolved, StateTended, "", false},
+		{StateArchived, StateEvolved, "", false},
+		{State("unknown"), StateResting, "", false},
+	}
+	for _, tt := range tests {
+		got, err := CheckTransition(tt.from, tt.to)
+		if !tt.ok {
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("CheckTransition(%s, %s) error = %v, want ErrInvalidTransition", tt.from, tt.to, err)
+			}
+			var transitionErr *TransitionError
+			if !errors.As(err, &transitionErr) || transitionErr.From != tt.from || transitionErr.To != tt.to {
+				t.Errorf("CheckTransition(%s, %s) error = %#v, want *TransitionError for the move", tt.from, tt.to, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("CheckTransition(%s, %s) error = %v", tt.from, tt.to, err)
+			continue
+		}
+		if got.Kind != tt.kind {
+			t.Errorf("CheckTransition(%s, %s) kind = %q, want %q", tt.from, tt.to, got.Kind, tt.kind)
+		}
+	}
+}
+
+func TestNextStates(t *testing.T) {
+	tests := []struct {
+		from State
+		want []State
+	}{
+		{StateCaptured, []State{StateTended, StateResting, StateArchived, StateEvolved, StateReleased}},
+		{StateTended, []State{StateResting, StateEvolved, StateReleased, StateArchived}},
+		{StateReleased, []State{StateResting}},
+		{State("unknown"), []State{}},
+	}
+	for _, tt := range tests {
+		if got := NextStates(tt.from); !slices.Equal(got, tt.want) {
+			t.Errorf("NextStates(%s) = %v, want %v", tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	tests := []struct {
+		state State
+		want  bool
+	}{
+		{StateCaptured, false},
+		{StateResting, false},
+		{StateTended, false},
+		{StateEvolved, true},
+		{StateReleased, true},
+		{StateArchived, true},
+	}
+	for _, tt := range tests {
+		if got := IsTerminal(tt.state); got != tt.want {
+			t.Errorf("IsTerminal(%s) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Replay reconstructs a thought as it stood at the moment at, from its events and content
+// revisions. base supplies the identity (ID, UID) and, where no event says otherwise, the
+// creation time. It reports false when the thought did not exist yet at that moment.
+//
+// State, tend count, last tended and eligibility come from the events; content comes from the
+// latest revision saved by then. Feelings are not replayed because their events only describe
+// the change, so Valence and Energy are left unset. Events and revisions must be ordered oldest first.
+func Replay(base Thought, events []Event, revisions []Revision, at time.Time) (Thought, bool) {
+	thought := Thought{
+		ID:        base.ID,
+		UID:       base.UID,
+		CreatedAt: base.CreatedAt,
+	}
+	for _, ev := range events {
+		if ev.Kind == EventCaptured {
+			thought.CreatedAt = ev.At
+			break
+		}
+	}
+	if thought.CreatedAt.IsZero() || thought.CreatedAt.After(at) {
+		return Thought{}, false
+	}
+
+	thought.CurrentState = StateCaptured
+	thought.UpdatedAt = thought.CreatedAt
+	for _, ev := range events {
+		if ev.At.After(at) {
+			break
+		}
+		// A shift after a pause only moves eligibility; it is not an edit of the thought.
+		if ev.Kind != EventShifted {
+			thought.UpdatedAt = ev.At
+		}
+		if ev.NextState != nil {
+			thought.CurrentState = *ev.NextState
+			if *ev.NextState == StateTended {
+				thought.TendCounter++
+				tendedAt := ev.At
+				thought.LastTendedAt = &tendedAt
+			}
+		}
+		if ev.EligibilityAt != nil {
+			thought.EligibilityAt = *ev.EligibilityAt
+		}
+	}
+
+	thought.Content = base.Content
+	for _, rev := range revisions {
+		if rev.CreatedAt.After(at) {
+			break
+		}
+		thought.Content = rev.Content
+	}
+	return thought, true
+}
+
+// EventsUntil returns the events that happened at or before at. Events must be ordered oldest first.
+func EventsUntil(events []Event, at time.Time) []Event {
+	for i, ev := range events {
+		if ev.At.After(at) {
+			return events[:i]
+		}
+	}
+	return events
+}
+
+// snapshotSlack is how far a snapshot timestamp may be from the event that set it and still
+// count as the same moment. Older peony wrote a new thought and its captured event in two
+// separate steps, a millisecond or so apart.
+const snapshotSlack = time.Second
+
+// KeepSnapshotTimes returns derived with the snapshot's created_at and updated_at wherever they
+// are within a second of the derived ones, so that such near misses are neither reported nor
+// rewritten by a repair.
+func KeepSnapshotTimes(snapshot, derived Thought) Thought {
+	near := func(a, b time.Time) bool {
+		d := a.Sub(b)
+		return d > -snapshotSlack && d < snapshotSlack
+	}
+	if near(snapshot.CreatedAt, derived.CreatedAt) {
+		derived.CreatedAt = snapshot.CreatedAt
+	}
+	if near(snapshot.UpdatedAt, derived.UpdatedAt) {
+		derived.UpdatedAt = snapshot.UpdatedAt
+	}
+	return derived
+}
+
+// Discrepancy is one field where a thought's stored snapshot disagrees with its history.
+type Discrepancy struct {
+	Field    string
+	Snapshot string
+	Derived  string
+}
+
+// CompareSnapshot lists the fields where snapshot differs from derived, the thought as replayed
+// from its history. Eligibility is only compared when the history records it, and content only
+// when revisions exist, so older histories without that information are not flagged.
+func CompareSnapshot(snapshot, derived Thought, hasRevisions bool) []Discrepancy {
+	diffs := make([]Discrepancy, 0)
+	add := func(field, snap, der string) {
+		if snap != der {
+			diffs = append(diffs, Discrepancy{Field: field, Snapshot: snap, Derived: der})
+		}
+	}
+	stamp := func(t time.Time) string {
+		if t.IsZero() {
+			return "(none)"
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	optionalStamp := func(t *time.Time) string {
+		if t == nil {
+			return "(none)"
+		}
+		return stamp(*t)
+	}
+
+	add("current_state", string(snapshot.CurrentState), string(derived.CurrentState))
+	add("tend_counter", fmt.Sprint(snapshot.TendCounter), fmt.Sprint(derived.TendCounter))
+	add("last_tended_at", optionalStamp(snapshot.LastTendedAt), optionalStamp(derived.LastTendedAt))
+	add("created_at", stamp(snapshot.CreatedAt), stamp(derived.CreatedAt))
+	add("updated_at", stamp(snapshot.UpdatedAt), stamp(derived.UpdatedAt))
+	if !derived.EligibilityAt.IsZero() {
+		add("eligibility_at", stamp(snapshot.EligibilityAt), stamp(derived.EligibilityAt))
+	}
+	if hasRevisions && snapshot.Content != derived.Content {
+		diffs = append(diffs, Discrepancy{Field: "content", Snapshot: "has an unrecorded edit", Derived: "keeps the latest revision"})
+	}
+	return diffs
+}
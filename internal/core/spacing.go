@@ -0,0 +1,103 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Spacing selects how rest intervals grow as a thought is tended again and again.
+type Spacing string
+
+const (
+	// SpacingFixed always rests for the settle duration.
+	SpacingFixed Spacing = "fixed"
+	// SpacingLinear adds half a settle duration for every tend after the first.
+	SpacingLinear Spacing = "linear"
+	// SpacingExponential grows the interval by half again for every tend after the first.
+	SpacingExponential Spacing = "exponential"
+)
+
+// DefaultSpacingCap is the longest interval a growing policy will choose unless configured otherwise.
+const DefaultSpacingCap = month
+
+// SpacingPolicy decides the rest interval for a thought from its tend count.
+type SpacingPolicy struct {
+	Spacing Spacing
+	Cap     time.Duration
+}
+
+// ResurfacePolicy is the spacing policy used when no explicit rest period is chosen.
+// It can be overridden via configuration.
+var ResurfacePolicy = SpacingPolicy{Spacing: SpacingFixed, Cap: DefaultSpacingCap}
+
+// ParseSpacing matches a spacing name case-insensitively.
+func ParseSpacing(s string) (Spacing, error) {
+	switch sp := Spacing(strings.ToLower(strings.TrimSpace(s))); sp {
+	case SpacingFixed, SpacingLinear, SpacingExponential:
+		return sp, nil
+	default:
+		return "", fmt.Errorf("unknown spacing %q (want fixed, linear or exponential)", s)
+	}
+}
+
+// Interval returns how long a thought tended tends times should rest, starting from base.
+// The result never drops below base and, for growing policies, never exceeds the cap.
+func (p SpacingPolicy) Interval(base time.Duration, tends int) time.Duration {
+	interval, _ := p.interval(base, tends)
+	return interval
+}
+
+// Explain describes the interval Interval would choose, for the event history and view.
+func (p SpacingPolicy) Explain(base time.Duration, tends int) string {
+	interval, capped := p.interval(base, tends)
+	if p.Spacing == SpacingFixed || p.Spacing == "" || tends <= 1 {
+		return "rest for " + FormatSpan(interval) + " (default)"
+	}
+
+	reason := fmt.Sprintf("rest for %s (%s spacing after %d tends", FormatSpan(interval), p.Spacing, tends)
+	if capped {
+		reason += ", capped at " + FormatSpan(p.Cap)
+	}
+	return reason + ")"
+}
+
+func (p SpacingPolicy) interval(base time.Duration, tends int) (time.Duration, bool) {
+	if base <= 0 || tends <= 1 {
+		return base, false
+	}
+
+	limit := p.Cap
+	if limit <= 0 {
+		limit = maxSpan
+	}
+	if limit < base {
+		limit = base
+	}
+
+	var interval time.Duration
+	switch p.Spacing {
+	case SpacingLinear:
+		interval = base + time.Duration(tends-1)*(base/2)
+	case SpacingExponential:
+		interval = base
+		for i := 1; i < tends && interval <= limit; i++ {
+			interval += interval / 2
+		}
+	default:
+		return base, false
+	}
+
+	if interval > limit {
+		return limit, p.Cap > 0
+	}
+	return interval, false
+}
+
+// SpacedRest returns the rest period chosen by ResurfacePolicy for a thought tended tends times.
+func SpacedRest(now time.Time, tends int) RestPeriod {
+	return RestPeriod{
+		Until:  now.Add(ResurfacePolicy.Interval(SettleDuration, tends)),
+		Reason: ResurfacePolicy.Explain(SettleDuration, tends),
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpacingPolicyInterval(t *testing.T) {
+	const base = 18 * time.Hour
+	tests := []struct {
+		name   string
+		policy SpacingPolicy
+		tends  int
+		want   time.Duration
+	}{
+		{"fixed", SpacingPolicy{Spacing: SpacingFixed, Cap: DefaultSpacingCap}, 5, base},
+		{"unset", SpacingPolicy{}, 5, base},
+		{"linear first tend", SpacingPolicy{Spacing: SpacingLinear, Cap: DefaultSpacingCap}, 1, base},
+		{"linear third tend", SpacingPolicy{Spacing: SpacingLinear, Cap: DefaultSpacingCap}, 3, 36 * time.Hour},
+		{"exponential first tend", SpacingPolicy{Spacing: SpacingExponential, Cap: DefaultSpacingCap}, 1, base},
+		{"exponential third tend", SpacingPolicy{Spacing: SpacingExponential, Cap: DefaultSpacingCap}, 3, 40*time.Hour + 30*time.Minute},
+		{"capped", SpacingPolicy{Spacing: SpacingExponential, Cap: 24 * time.Hour}, 3, 24 * time.Hour},
+		{"cap below base", SpacingPolicy{Spacing: SpacingLinear, Cap: time.Hour}, 4, base},
+		{"many tends stay capped", SpacingPolicy{Spacing: SpacingExponential, Cap: DefaultSpacingCap}, 500, DefaultSpacingCap},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Interval(base, tt.tends); got != tt.want {
+			t.Errorf("%s: Interval(%s, %d) = %s, want %s", tt.name, base, tt.tends, got, tt.want)
+		}
+	}
+}
+
+func TestSpacingPolicyExplain(t *testing.T) {
+	const base = 18 * time.Hour
+	tests := []struct {
+		policy SpacingPolicy
+		tends  int
+		want   string
+	}{
+		{SpacingPolicy{Spacing: SpacingFixed}, 4, "rest for " + FormatSpan(base) + " (default)"},
+		{SpacingPolicy{Spacing: SpacingLinear, Cap: DefaultSpacingCap}, 1, "rest for " + FormatSpan(base) + " (default)"},
+		{SpacingPolicy{Spacing: SpacingLinear, Cap: DefaultSpacingCap}, 3, "rest for " + FormatSpan(36*time.Hour) + " (linear spacing after 3 tends)"},
+		{SpacingPolicy{Spacing: SpacingExponential, Cap: 24 * time.Hour}, 3, "rest for " + FormatSpan(24*time.Hour) + " (exponential spacing after 3 tends, capped at " + FormatSpan(24*time.Hour) + ")"},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Explain(base, tt.tends); got != tt.want {
+			t.Errorf("%+v.Explain(%d) = %q, want %q", tt.policy, tt.tends, got, tt.want)
+		}
+	}
+}
+
+func TestParseSpacing(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Spacing
+		wantErr bool
+	}{
+		{"fixed", SpacingFixed, false},
+		{" Linear ", SpacingLinear, false},
+		{"EXPONENTIAL", SpacingExponential, false},
+		{"fibonacci", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpacing(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSpacing(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
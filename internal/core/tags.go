@@ -0,0 +1,83 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MaxTagLength bounds a single tag so tags stay short labels rather than sentences.
+const MaxTagLength = 32
+
+// ParseTags reads a comma- or space-separated list of tags, e.g. "cabin, ideas work".
+// Tags are lowercased, deduplicated and sorted; an empty string yields no tags.
+func ParseTags(s string) ([]string, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	seen := make(map[string]struct{}, len(fields))
+	tags := make([]string, 0, len(fields))
+	for _, field := range fields {
+		tag, err := NormalizeTag(field)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// NormalizeTag lowercases a tag and checks it holds only letters, digits, '-', '_' or '/'.
+func NormalizeTag(s string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "#")))
+	if tag == "" {
+		return "", fmt.Errorf("empty tag")
+	}
+	if len(tag) > MaxTagLength {
+		return "", fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
+	}
+	for _, r := range tag {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '/':
+		default:
+			return "", fmt.Errorf("invalid tag %q (use letters, digits, '-', '_' or '/')", tag)
+		}
+	}
+	return tag, nil
+}
+
+// FormatTags renders tags as they are written in the tend template, e.g. "cabin, ideas".
+func FormatTags(tags []string) string {
+	return strings.Join(tags, ", ")
+}
+
+// DescribeTagChange summarises how tags moved from prev to next, e.g. "+cabin -work".
+// Both lists are expected sorted, as ParseTags returns them.
+func DescribeTagChange(prev, next []string) string {
+	had := make(map[string]struct{}, len(prev))
+	for _, tag := range prev {
+		had[tag] = struct{}{}
+	}
+	has := make(map[string]struct{}, len(next))
+	for _, tag := range next {
+		has[tag] = struct{}{}
+	}
+
+	parts := make([]string, 0, len(prev)+len(next))
+	for _, tag := range next {
+		if _, ok := had[tag]; !ok {
+			parts = append(parts, "+"+tag)
+		}
+	}
+	for _, tag := range prev {
+		if _, ok := has[tag]; !ok {
+			parts = append(parts, "-"+tag)
+		}
+	}
+	return strings.Join(parts, " ")
+}
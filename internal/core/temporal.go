@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -28,5 +29,75 @@ func EligibleToSurface(thought Thought, now time.Time) bool {
 	}
 
 	// Eligibility is reached once now is at or after eligibility_at.
-	return !now.Before(thought.EligibilityAt)
+	if now.Before(thought.EligibilityAt) {
+		return false
+	}
+
+	// Ripe thoughts still wait for an open surfacing window.
+	return Windows.Open(now)
+}
+
+// RestPeriod describes when a resting thought may surface again and how that moment was chosen.
+type RestPeriod struct {
+	Until  time.Time
+	Reason string
+}
+
+// RestFor returns a rest period that ends d after now.
+func RestFor(now time.Time, d time.Duration) RestPeriod {
+	return RestPeriod{
+		Until:  now.Add(d),
+		Reason: "rest for " + FormatSpan(d),
+	}
+}
+
+// RestUntil returns a rest period that ends at the given moment.
+func RestUntil(until time.Time) RestPeriod {
+	layout := "2006-01-02 15:04"
+	if until.Equal(startOfDay(until)) {
+		layout = "2006-01-02"
+	}
+	return RestPeriod{
+		Until:  until,
+		Reason: "rest until " + until.Format(layout),
+	}
+}
+
+// DefaultRest returns the rest period implied by SettleDuration.
+func DefaultRest(now time.Time) RestPeriod {
+	return RestPeriod{
+		Until:  now.Add(SettleDuration),
+		Reason: "rest for " + FormatSpan(SettleDuration) + " (default)",
+	}
+}
+
+// FormatSpan renders a duration in compact calendar units, e.g. 1mo, 2w, 1w2d, 18h, 1d12h.
+// Its output is always accepted by ParseSpan.
+func FormatSpan(d time.Duration) string {
+	if d <= 0 {
+		return "0m"
+	}
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"mo", month},
+		{"w", week},
+		{"d", day},
+		{"h", time.Hour},
+		{"m", time.Minute},
+	}
+
+	out := ""
+	for _, u := range units {
+		if n := d / u.size; n > 0 {
+			out += fmt.Sprintf("%d%s", n, u.suffix)
+			d -= n * u.size
+		}
+	}
+	if out == "" {
+		return d.String()
+	}
+	return out
 }
@@ -6,6 +6,7 @@ import (
 
 // State represents the lifecycle state of a thought.
 type State string
+
 const (
 	StateCaptured State = "captured"
 	StateResting  State = "resting"
@@ -18,6 +19,7 @@ const (
 // Thought represents the current snapshot of a cognitive unit.
 type Thought struct {
 	ID            int64      `db:"id"`
+	UID           string     `db:"uid"`
 	Content       string     `db:"content"`
 	CurrentState  State      `db:"current_state"`
 	TendCounter   int        `db:"tend_counter"`
@@ -27,15 +29,30 @@ type Thought struct {
 	EligibilityAt time.Time  `db:"eligibility_at"`
 	Valence       *int       `db:"valence"`
 	Energy        *int       `db:"energy"`
+	// Tags is sorted and only filled when a single thought is read.
+	Tags []string `db:"-"`
 }
 
 // Event represents a single append-only history record for a thought.
 type Event struct {
-	ID            int64     `db:"id"`
-	ThoughtID     int64     `db:"thought_id"`
-	Kind          string    `db:"kind"`
-	At            time.Time `db:"at"`
-	PreviousState *State    `db:"previous_state"`
-	NextState     *State    `db:"next_state"`
-	Note          *string   `db:"note"`
+	ID            int64      `db:"id"`
+	ThoughtID     int64      `db:"thought_id"`
+	Kind          string     `db:"kind"`
+	At            time.Time  `db:"at"`
+	PreviousState *State     `db:"previous_state"`
+	NextState     *State     `db:"next_state"`
+	Note          *string    `db:"note"`
+	EligibilityAt *time.Time `db:"eligibility_at"`
+	Detail        *string    `db:"detail"`
+}
+
+// Revision is one saved version of a thought's content, linked to the event that produced it.
+// EventID is nil for a first revision backfilled from a thought whose events were missing.
+type Revision struct {
+	ID        int64     `db:"id"`
+	ThoughtID int64     `db:"thought_id"`
+	EventID   *int64    `db:"event_id"`
+	Number    int       `db:"number"`
+	Content   string    `db:"content"`
+	CreatedAt time.Time `db:"created_at"`
 }
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	day   = 24 * time.Hour
+	week  = 7 * day
+	month = 30 * day
+	year  = 365 * day
+)
+
+// maxSpan bounds parsed spans so arithmetic on them can never overflow.
+const maxSpan = 100 * year
+
+// spanUnits maps every accepted unit spelling to its length. Months and years are fixed-length approximations.
+var spanUnits = map[string]time.Duration{
+	"y": year, "yr": year, "yrs": year, "year": year, "years": year,
+	"mo": month, "mon": month, "month": month, "months": month,
+	"fortnight": 2 * week, "fortnights": 2 * week,
+	"w": week, "wk": week, "wks": week, "week": week, "weeks": week,
+	"d": day, "day": day, "days": day,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+}
+
+// ParseSpan parses a human-friendly duration such as 3d, 2w, 1mo, 1w2d, 36h, 2h30m,
+// "2 weeks and 3 days" or "in a fortnight".
+func ParseSpan(s string) (time.Duration, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	if text == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	if d, err := time.ParseDuration(text); err == nil {
+		if d <= 0 {
+			return 0, fmt.Errorf("duration %q must be positive", s)
+		}
+		return d, nil
+	}
+
+	tokens := spanTokens(text)
+	if len(tokens) > 0 && tokens[0] == "in" {
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var total time.Duration
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok == "and" {
+			continue
+		}
+
+		count := int64(1)
+		if n, err := strconv.ParseInt(tok, 10, 64); err == nil {
+			count = n
+			i++
+		} else if tok == "a" || tok == "an" {
+			i++
+		}
+		if i >= len(tokens) {
+			return 0, fmt.Errorf("invalid duration %q: missing unit", s)
+		}
+
+		unit, ok := spanUnits[tokens[i]]
+		if !ok {
+			return 0, fmt.Errorf("invalid duration %q: unknown unit %q", s, tokens[i])
+		}
+		if count <= 0 || count > int64(maxSpan/unit) {
+			return 0, fmt.Errorf("invalid duration %q: out of range", s)
+		}
+		total += time.Duration(count) * unit
+		if total > maxSpan {
+			return 0, fmt.Errorf("invalid duration %q: out of range", s)
+		}
+	}
+
+	if total <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return total, nil
+}
+
+// spanTokens splits text at spaces and commas and wherever digits meet letters, so "1w2d" becomes [1 w 2 d].
+func spanTokens(text string) []string {
+	tokens := make([]string, 0)
+	var current strings.Builder
+	var currentIsDigit bool
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r) || r == ',' || r == '+':
+			flush()
+		case unicode.IsDigit(r):
+			if current.Len() > 0 && !currentIsDigit {
+				flush()
+			}
+			currentIsDigit = true
+			current.WriteRune(r)
+		default:
+			if current.Len() > 0 && currentIsDigit {
+				flush()
+			}
+			currentIsDigit = false
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// dateLayouts are the absolute date forms accepted by ParseWhen, interpreted in now's location.
+var dateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	time.RFC3339,
+}
+
+// ParseWhen parses a moment relative to now. It accepts ISO dates (2027-01-15), "today",
+// "tomorrow", "yesterday", weekdays ("monday", "next monday"), "next/last week/month/year",
+// any span accepted by ParseSpan, optionally prefixed by "in" ("in a fortnight"), and spans
+// in the past ("3 months ago").
+// Calendar dates and weekdays resolve to the start of that day in now's location.
+func ParseWhen(s string, now time.Time) (time.Time, error) {
+	at, _, err := parseWhen(s, now)
+	return at, err
+}
+
+// parseWhen is ParseWhen, also reporting whether s named a whole calendar day rather than a moment.
+func parseWhen(s string, now time.Time) (time.Time, bool, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	if text == "" {
+		return time.Time{}, false, fmt.Errorf("empty date")
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(text), now.Location()); err == nil {
+			return t, layout == dateLayouts[0], nil
+		}
+	}
+
+	today := startOfDay(now)
+	switch text {
+	case "today":
+		return today, true, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true, nil
+	case "next week":
+		return now.AddDate(0, 0, 7), false, nil
+	case "next month":
+		return now.AddDate(0, 1, 0), false, nil
+	case "next year":
+		return now.AddDate(1, 0, 0), false, nil
+	case "last week":
+		return now.AddDate(0, 0, -7), false, nil
+	case "last month":
+		return now.AddDate(0, -1, 0), false, nil
+	case "last year":
+		return now.AddDate(-1, 0, 0), false, nil
+	}
+
+	if span, ok := strings.CutSuffix(text, " ago"); ok {
+		d, err := ParseSpan(span)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unrecognised date %q", s)
+		}
+		return now.Add(-d), false, nil
+	}
+
+	if weekday, ok := parseWeekday(strings.TrimPrefix(text, "next ")); ok {
+		ahead := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if ahead == 0 {
+			ahead = 7
+		}
+		return today.AddDate(0, 0, ahead), true, nil
+	}
+
+	if d, err := ParseSpan(text); err == nil {
+		return now.Add(d), false, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf("unrecognised date %q", s)
+}
+
+// ParseAsOf parses a moment to look back from, as accepted by ParseWhen. A bare calendar date
+// means the end of that day, so "2026-06-01" includes everything that happened on June 1st,
+// while "2026-06-01 00:00" stays at midnight. Moments in the future are clamped to now.
+func ParseAsOf(s string, now time.Time) (time.Time, error) {
+	at, wholeDay, err := parseWhen(s, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if wholeDay {
+		at = at.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	if at.After(now) {
+		at = now
+	}
+	return at, nil
+}
+
+// ParseRest turns a duration ("3d", "in a fortnight") or a moment ("2027-01-15", "next monday")
+// into a rest period starting at now. Moments must lie in the future.
+func ParseRest(s string, now time.Time) (RestPeriod, error) {
+	if d, err := ParseSpan(s); err == nil {
+		return RestFor(now, d), nil
+	}
+
+	until, err := ParseWhen(s, now)
+	if err != nil {
+		return RestPeriod{}, err
+	}
+	if !until.After(now) {
+		return RestPeriod{}, fmt.Errorf("%q is not in the future", s)
+	}
+	return RestUntil(until), nil
+}
+
+// parseWeekday matches full and three-letter weekday names.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			return wd, true
+		}
+	}
+	return time.Sunday, false
+}
+
+// startOfDay returns midnight of t's calendar day in t's location.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
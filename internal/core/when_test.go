@@ -0,0 +1,127 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSpan(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"3d", 3 * day, false},
+		{"2w", 2 * week, false},
+		{"1mo", month, false},
+		{"1w2d", week + 2*day, false},
+		{"90m", 90 * time.Minute, false},
+		{"2h30m", 2*time.Hour + 30*time.Minute, false},
+		{" 2 Weeks and 3 days ", 2*week + 3*day, false},
+		{"in a fortnight", 2 * week, false},
+		{"an hour", time.Hour, false},
+		{"0d", 0, true},
+		{"0s", 0, true},
+		{"-3d", 0, true},
+		{"-3h", 0, true},
+		{"99999999w", 0, true},
+		{"3 months ago", 0, true},
+		{"3", 0, true},
+		{"soon", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpan(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSpan(%q) = %s, %v; want %s, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseWhen(t *testing.T) {
+	now := mondayAt(0, 10, 30)
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2027-01-15", time.Date(2027, time.January, 15, 0, 0, 0, 0, time.Local), false},
+		{"2027-01-15 09:45", time.Date(2027, time.January, 15, 9, 45, 0, 0, time.Local), false},
+		{"2027-01-15t09:45", time.Date(2027, time.January, 15, 9, 45, 0, 0, time.Local), false},
+		{"today", mondayAt(0, 0, 0), false},
+		{"Tomorrow", mondayAt(1, 0, 0), false},
+		{"yesterday", mondayAt(-1, 0, 0), false},
+		{"next monday", mondayAt(7, 0, 0), false},
+		{"monday", mondayAt(7, 0, 0), false},
+		{"wed", mondayAt(2, 0, 0), false},
+		{"next week", mondayAt(7, 10, 30), false},
+		{"in a fortnight", mondayAt(14, 10, 30), false},
+		{"3d", mondayAt(3, 10, 30), false},
+		{"3 months ago", now.Add(-3 * month), false},
+		{"0d ago", time.Time{}, true},
+		{"99999999w", time.Time{}, true},
+		{"2027-02-30", time.Time{}, true},
+		{"someday", time.Time{}, true},
+		{"", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWhen(tt.in, now)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("ParseWhen(%q) = %s, %v; want %s, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseRest(t *testing.T) {
+	now := mondayAt(0, 10, 30)
+	tests := []struct {
+		in         string
+		wantUntil  time.Time
+		wantReason string
+		wantErr    bool
+	}{
+		{"3d", mondayAt(3, 10, 30), "rest for 3d", false},
+		{"1w2d", mondayAt(9, 10, 30), "rest for 1w2d", false},
+		{"in a fortnight", mondayAt(14, 10, 30), "rest for 2w", false},
+		{"next monday", mondayAt(7, 0, 0), "rest until 2026-06-08", false},
+		{"2026-06-03 18:00", mondayAt(2, 18, 0), "rest until 2026-06-03 18:00", false},
+		{"today", time.Time{}, "", true},
+		{"3 months ago", time.Time{}, "", true},
+		{"-3d", time.Time{}, "", true},
+		{"0d", time.Time{}, "", true},
+		{"99999999w", time.Time{}, "", true},
+		{"whenever", time.Time{}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRest(tt.in, now)
+		if (err != nil) != tt.wantErr || !got.Until.Equal(tt.wantUntil) || got.Reason != tt.wantReason {
+			t.Errorf("ParseRest(%q) = %+v, %v; want until %s (%q), error %v", tt.in, got, err, tt.wantUntil, tt.wantReason, tt.wantErr)
+		}
+	}
+}
+
+func TestParseAsOf(t *testing.T) {
+	now := mondayAt(9, 10, 30)
+	endOf := func(days int) time.Time { return mondayAt(days+1, 0, 0).Add(-time.Nanosecond) }
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"2026-06-01", endOf(0), false},
+		{"2026-06-01 00:00", mondayAt(0, 0, 0), false},
+		{"2026-06-01 12:15", mondayAt(0, 12, 15), false},
+		{"yesterday", endOf(8), false},
+		{"today", now, false},
+		{"tomorrow", now, false},
+		{"3 days ago", mondayAt(6, 10, 30), false},
+		{"3d", now, false},
+		{"someday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAsOf(tt.in, now)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("ParseAsOf(%q) = %s, %v; want %s, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DailyWindow is a span of local clock time, e.g. 18:00-22:00. A window whose end is
+// before its start wraps past midnight (23:00-07:00).
+type DailyWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Contains reports whether the clock time of t falls inside the window.
+func (w DailyWindow) Contains(t time.Time) bool {
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start <= w.End {
+		return clock >= w.Start && clock < w.End
+	}
+	return clock >= w.Start || clock < w.End
+}
+
+func (w DailyWindow) String() string {
+	return formatClock(w.Start) + "-" + formatClock(w.End)
+}
+
+// SurfacingWindows restricts when ripe thoughts may surface. Times are evaluated in local time.
+type SurfacingWindows struct {
+	// Reflect, when non-empty, limits surfacing to these windows.
+	Reflect []DailyWindow
+	// Quiet windows never surface anything, even inside a reflection window.
+	Quiet []DailyWindow
+	// QuietDays never surface anything.
+	QuietDays []time.Weekday
+}
+
+// Windows holds the surfacing windows in effect. It can be overridden via configuration.
+var Windows SurfacingWindows
+
+// Open reports whether thoughts may surface at now.
+func (w SurfacingWindows) Open(now time.Time) bool {
+	local := now.Local()
+	for _, wd := range w.QuietDays {
+		if local.Weekday() == wd {
+			return false
+		}
+	}
+	for _, q := range w.Quiet {
+		if q.Contains(local) {
+			return false
+		}
+	}
+	if len(w.Reflect) == 0 {
+		return true
+	}
+	for _, r := range w.Reflect {
+		if r.Contains(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe summarises the windows in a short human sentence, or "" when surfacing is unrestricted.
+func (w SurfacingWindows) Describe() string {
+	parts := make([]string, 0, 3)
+	if len(w.Reflect) > 0 {
+		parts = append(parts, "between "+FormatWindows(w.Reflect))
+	}
+	if len(w.Quiet) > 0 {
+		parts = append(parts, "never during "+FormatWindows(w.Quiet))
+	}
+	if len(w.QuietDays) > 0 {
+		parts = append(parts, "never on "+FormatWeekdays(w.QuietDays))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseWindows parses a comma-separated list of HH:MM-HH:MM windows. "none" or "" yields no windows.
+func ParseWindows(s string) ([]DailyWindow, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	if text == "" || text == "none" {
+		return nil, nil
+	}
+
+	windows := make([]DailyWindow, 0)
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		startStr, endStr, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid window %q (want HH:MM-HH:MM)", part)
+		}
+		start, err := parseClock(startStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %q: %w", part, err)
+		}
+		end, err := parseClock(endStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window %q: %w", part, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("invalid window %q: start and end are the same", part)
+		}
+		windows = append(windows, DailyWindow{Start: start, End: end})
+	}
+	return windows, nil
+}
+
+// FormatWindows renders windows in the form accepted by ParseWindows.
+func FormatWindows(windows []DailyWindow) string {
+	parts := make([]string, 0, len(windows))
+	for _, w := range windows {
+		parts = append(parts, w.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseWeekdays parses a comma-separated list of weekday names, or the shorthands
+// "weekdays" and "weekends". "none" or "" yields no days.
+func ParseWeekdays(s string) ([]time.Weekday, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	if text == "" || text == "none" {
+		return nil, nil
+	}
+
+	seen := make(map[time.Weekday]struct{})
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		switch part {
+		case "weekdays":
+			for wd := time.Monday; wd <= time.Friday; wd++ {
+				seen[wd] = struct{}{}
+			}
+		case "weekends":
+			seen[time.Saturday] = struct{}{}
+			seen[time.Sunday] = struct{}{}
+		default:
+			wd, ok := parseWeekday(part)
+			if !ok {
+				return nil, fmt.Errorf("unknown weekday %q", part)
+			}
+			seen[wd] = struct{}{}
+		}
+	}
+
+	days := make([]time.Weekday, 0, len(seen))
+	for wd := range seen {
+		days = append(days, wd)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i] < days[j] })
+	return days, nil
+}
+
+// FormatWeekdays renders days in the form accepted by ParseWeekdays.
+func FormatWeekdays(days []time.Weekday) string {
+	parts := make([]string, 0, len(days))
+	for _, wd := range days {
+		parts = append(parts, strings.ToLower(wd.String()[:3]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseClock parses HH:MM (or H) into an offset from midnight. 24:00 is accepted as end of day.
+func parseClock(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, ":") {
+		s += ":00"
+	}
+	var h, m int
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
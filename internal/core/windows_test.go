@@ -0,0 +1,119 @@
+package core
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// mondayAt returns 2026-06-01 (a Monday) at hh:mm local time, shifted by days.
+func mondayAt(days, hh, mm int) time.Time {
+	return time.Date(2026, time.June, 1+days, hh, mm, 0, 0, time.Local)
+}
+
+func TestDailyWindowContains(t *testing.T) {
+	evening := DailyWindow{Start: 18 * time.Hour, End: 22 * time.Hour}
+	night := DailyWindow{Start: 23 * time.Hour, End: 7 * time.Hour}
+	tests := []struct {
+		window DailyWindow
+		at     time.Time
+		want   bool
+	}{
+		{evening, mondayAt(0, 18, 0), true},
+		{evening, mondayAt(0, 21, 59), true},
+		{evening, mondayAt(0, 22, 0), false},
+		{evening, mondayAt(0, 9, 0), false},
+		{night, mondayAt(0, 23, 30), true},
+		{night, mondayAt(0, 3, 0), true},
+		{night, mondayAt(0, 7, 0), false},
+		{night, mondayAt(0, 12, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.window.Contains(tt.at); got != tt.want {
+			t.Errorf("%s.Contains(%s) = %v, want %v", tt.window, tt.at.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestSurfacingWindowsOpen(t *testing.T) {
+	evening := []DailyWindow{{Start: 18 * time.Hour, End: 22 * time.Hour}}
+	late := []DailyWindow{{Start: 21 * time.Hour, End: 23 * time.Hour}}
+	tests := []struct {
+		name    string
+		windows SurfacingWindows
+		at      time.Time
+		want    bool
+	}{
+		{"unrestricted", SurfacingWindows{}, mondayAt(0, 3, 0), true},
+		{"inside reflect", SurfacingWindows{Reflect: evening}, mondayAt(0, 19, 0), true},
+		{"outside reflect", SurfacingWindows{Reflect: evening}, mondayAt(0, 12, 0), false},
+		{"quiet wins over reflect", SurfacingWindows{Reflect: evening, Quiet: late}, mondayAt(0, 21, 30), false},
+		{"quiet alone", SurfacingWindows{Quiet: late}, mondayAt(0, 12, 0), true},
+		{"quiet day", SurfacingWindows{QuietDays: []time.Weekday{time.Monday}}, mondayAt(0, 19, 0), false},
+		{"other day", SurfacingWindows{QuietDays: []time.Weekday{time.Monday}}, mondayAt(1, 19, 0), true},
+	}
+	for _, tt := range tests {
+		if got := tt.windows.Open(tt.at); got != tt.want {
+			t.Errorf("%s: Open(%s) = %v, want %v", tt.name, tt.at.Format("Mon 15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestParseWindows(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []DailyWindow
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"none", nil, false},
+		{"18:00-22:00", []DailyWindow{{Start: 18 * time.Hour, End: 22 * time.Hour}}, false},
+		{"7-9, 23:30-24:00", []DailyWindow{
+			{Start: 7 * time.Hour, End: 9 * time.Hour},
+			{Start: 23*time.Hour + 30*time.Minute, End: 24 * time.Hour},
+		}, false},
+		{"18:00", nil, true},
+		{"18:00-18:00", nil, true},
+		{"25:00-26:00", nil, true},
+		{"18:60-19:00", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindows(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWindows(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseWindows(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+		if err == nil && len(got) > 0 {
+			if again, _ := ParseWindows(FormatWindows(got)); !slices.Equal(again, got) {
+				t.Errorf("ParseWindows(FormatWindows(%v)) = %v", got, again)
+			}
+		}
+	}
+}
+
+func TestParseWeekdays(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []time.Weekday
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"weekends", []time.Weekday{time.Sunday, time.Saturday}, false},
+		{"sun, monday, mon", []time.Weekday{time.Sunday, time.Monday}, false},
+		{"weekdays", []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, false},
+		{"someday", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWeekdays(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseWeekdays(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseWeekdays(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// GetThoughtAsOf reconstructs a thought as it stood at the moment at, together with the events
+// that had happened by then. It fails when the thought did not exist yet.
+func (s *Store) GetThoughtAsOf(id int64, at time.Time) (core.Thought, []core.Event, error) {
+	if s == nil {
+		return core.Thought{}, nil, fmt.Errorf("get thought as of: store is nil")
+	}
+	if s.db == nil {
+		return core.Thought{}, nil, fmt.Errorf("get thought as of: db is nil")
+	}
+
+	current, events, err := s.GetThought(id)
+	if err != nil {
+		return core.Thought{}, nil, fmt.Errorf("get thought as of: %w", err)
+	}
+	revisions, err := s.revisionsFor(id)
+	if err != nil {
+		return core.Thought{}, nil, fmt.Errorf("get thought as of: revisions: %w", err)
+	}
+
+	thought, ok := core.Replay(current, events, revisions, at)
+	if !ok {
+		return core.Thought{}, nil, fmt.Errorf("get thought as of: #%d had not been captured yet", id)
+	}
+	return thought, core.EventsUntil(events, at), nil
+}
+
+// ListThoughtsAsOf reconstructs every thought that existed at the moment at, ordered by ID.
+// Purged thoughts are gone for good and cannot be replayed.
+func (s *Store) ListThoughtsAsOf(at time.Time) ([]core.Thought, error) {
+	if s == nil {
+		return nil, fmt.Errorf("list thoughts as of: store is nil")
+	}
+	if s.db == nil {
+		return nil, fmt.Errorf("list thoughts as of: db is nil")
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id FROM thoughts WHERE created_at <= ? ORDER BY id ASC`,
+		formatTime(at),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list thoughts as of: query: %w", err)
+	}
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("list thoughts as of: scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("list thoughts as of: rows: %w", err)
+	}
+	rows.Close()
+
+	thoughts := make([]core.Thought, 0, len(ids))
+	for _, id := range ids {
+		current, events, err := s.GetThought(id)
+		if err != nil {
+			return nil, fmt.Errorf("list thoughts as of: %w", err)
+		}
+		revisions, err := s.revisionsFor(id)
+		if err != nil {
+			return nil, fmt.Errorf("list thoughts as of: revisions: %w", err)
+		}
+		if thought, ok := core.Replay(current, events, revisions, at); ok {
+			thoughts = append(thoughts, thought)
+		}
+	}
+	return thoughts, nil
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// appStateKeyDailySelection holds today's core.DaySelection as JSON.
+const appStateKeyDailySelection = "daily_selection"
+
+// todaysSelection returns the IDs of the ripe thoughts offered today under core.DailyBudget.
+// limited is false when no budget applies, in which case every ripe thought is offered.
+// The day's selection is pinned in app_state and a thought keeps its slot for the whole day once
+// it has been offered. Most calls only read the pin; a new pin is written inside one immediate
+// transaction, after reading everything again, so two peonies agree on it.
+func (s *Store) todaysSelection(now time.Time) (selected map[int64]struct{}, limited bool, err error) {
+	if core.DailyBudget <= 0 {
+		return nil, false, nil
+	}
+
+	ripe, pinned, changed, err := pinDaySelection(s.db, now)
+	if err != nil {
+		return nil, true, fmt.Errorf("daily selection: %w", err)
+	}
+	if changed {
+		tx, err := s.beginTx()
+		if err != nil {
+			return nil, true, fmt.Errorf("daily selection: begin tx: %w", err)
+		}
+		defer func() {
+			_ = tx.Rollback()
+		}()
+
+		ripe, pinned, changed, err = pinDaySelection(tx, now)
+		if err != nil {
+			return nil, true, fmt.Errorf("daily selection: %w", err)
+		}
+		if changed {
+			if err := writeDaySelectionTx(tx, pinned, formatTime(now)); err != nil {
+				return nil, true, fmt.Errorf("daily selection: %w", err)
+			}
+			if err := tx.Commit(); err != nil {
+				return nil, true, fmt.Errorf("daily selection: commit: %w", err)
+			}
+		}
+	}
+
+	selected = make(map[int64]struct{})
+	for _, thought := range ripe {
+		if pinned.Includes(thought.UID) {
+			selected[thought.ID] = struct{}{}
+		}
+	}
+	return selected, true, nil
+}
+
+// pinDaySelection reads the ripe thoughts and the pinned selection, and reports the selection
+// core.PinForDay makes of them and whether it differs from the pinned one.
+func pinDaySelection(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}, now time.Time) ([]core.Thought, core.DaySelection, bool, error) {
+	ripe, err := ripeThoughts(q, now)
+	if err != nil {
+		return nil, core.DaySelection{}, false, err
+	}
+	pinned, err := readDaySelection(q)
+	if err != nil {
+		return nil, core.DaySelection{}, false, err
+	}
+	tended, err := tendedOn(q, now)
+	if err != nil {
+		return nil, core.DaySelection{}, false, err
+	}
+
+	pinned, changed := core.PinForDay(pinned, ripe, tended, now, core.DailyBudget)
+	return ripe, pinned, changed, nil
+}
+
+// CountHeldBack returns how many ripe thoughts the daily budget is keeping back today.
+func (s *Store) CountHeldBack() (int, error) {
+	if s == nil {
+		return 0, fmt.Errorf("count held back: store is nil")
+	}
+	if s.db == nil {
+		return 0, fmt.Errorf("count held back: db is nil")
+	}
+
+	nowTime := time.Now()
+	if !core.Windows.Open(nowTime) {
+		return 0, nil
+	}
+	selected, limited, err := s.todaysSelection(nowTime)
+	if err != nil || !limited {
+		return 0, err
+	}
+	ripe, err := ripeThoughts(s.db, nowTime)
+	if err != nil {
+		return 0, fmt.Errorf("count held back: %w", err)
+	}
+	return len(ripe) - len(selected), nil
+}
+
+// ripeThoughts returns the ID, UID and eligibility of every thought eligible to tend at now.
+func ripeThoughts(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, now time.Time) ([]core.Thought, error) {
+	rows, err := q.Query(
+		`SELECT id, uid, eligibility_at
+		 FROM thoughts
+		 WHERE current_state IN (?, ?)
+		   AND eligibility_at <= ?`,
+		string(core.StateCaptured),
+		string(core.StateResting),
+		formatTime(now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query ripe: %w", err)
+	}
+	defer rows.Close()
+
+	ripe := make([]core.Thought, 0)
+	for rows.Next() {
+		var thought core.Thought
+		var eligibilityAtStr string
+		if err := rows.Scan(&thought.ID, &thought.UID, &eligibilityAtStr); err != nil {
+			return nil, fmt.Errorf("scan ripe: %w", err)
+		}
+		thought.EligibilityAt, err = parseTime(eligibilityAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse eligibility_at: %w", err)
+		}
+		ripe = append(ripe, thought)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ripe rows: %w", err)
+	}
+	return ripe, nil
+}
+
+// tendedOn returns the UIDs of thoughts tended on the local day containing now.
+func tendedOn(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, now time.Time) ([]string, error) {
+	local := now.Local()
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	rows, err := q.Query(
+		`SELECT DISTINCT t.uid
+		 FROM events e
+		 JOIN thoughts t ON t.id = e.thought_id
+		 WHERE e.kind = ?
+		   AND e.next_state = ?
+		   AND e.at >= ?`,
+		core.EventStateChange,
+		string(core.StateTended),
+		formatTime(dayStart),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query tended today: %w", err)
+	}
+	defer rows.Close()
+
+	uids := make([]string, 0)
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, fmt.Errorf("scan tended today: %w", err)
+		}
+		uids = append(uids, uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("tended today rows: %w", err)
+	}
+	return uids, nil
+}
+
+// readDaySelection returns the pinned selection, or a zero one when none was pinned yet.
+func readDaySelection(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (core.DaySelection, error) {
+	var value string
+	err := q.QueryRow(`SELECT value FROM app_state WHERE key = ?`, appStateKeyDailySelection).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return core.DaySelection{}, nil
+	}
+	if err != nil {
+		return core.DaySelection{}, fmt.Errorf("read %s: %w", appStateKeyDailySelection, err)
+	}
+	var sel core.DaySelection
+	if err := json.Unmarshal([]byte(value), &sel); err != nil {
+		// A damaged pin is replaced by a fresh selection for today.
+		return core.DaySelection{}, nil
+	}
+	return sel, nil
+}
+
+// writeDaySelectionTx pins sel in app_state.
+func writeDaySelectionTx(tx *sql.Tx, sel core.DaySelection, at string) error {
+	data, err := json.Marshal(sel)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", appStateKeyDailySelection, err)
+	}
+	_, err = tx.Exec(
+		`INSERT INTO app_state(key, value, updated_at)
+		 VALUES (?, ?, ?)
+		 ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		appStateKeyDailySelection,
+		string(data),
+		at,
+	)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", appStateKeyDailySelection, err)
+	}
+	return nil
+}
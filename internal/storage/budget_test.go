@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// tendIDs lists the IDs of every thought currently offered to tend.
+func tendIDs(t *testing.T, st *Store) []int64 {
+	t.Helper()
+	thoughts, err := st.ListTendThoughtsByPagination(50, 0)
+	if err != nil {
+		t.Fatalf("list tend thoughts: %v", err)
+	}
+	ids := make([]int64, 0, len(thoughts))
+	for _, thought := range thoughts {
+		ids = append(ids, thought.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestDailySelectionIsStableWithinADay(t *testing.T) {
+	st := newTestStore(t)
+	prevBudget := core.DailyBudget
+	core.DailyBudget = 2
+	t.Cleanup(func() { core.DailyBudget = prevBudget })
+
+	for i := 0; i < 5; i++ {
+		addRipeThought(t, st, fmt.Sprintf("thought %d", i))
+	}
+
+	first := tendIDs(t, st)
+	if len(first) != 2 {
+		t.Fatalf("offered %v, want 2 thoughts", first)
+	}
+	if again := tendIDs(t, st); !slices.Equal(first, again) {
+		t.Fatalf("selection changed between reads: %v then %v", first, again)
+	}
+	held, err := st.CountHeldBack()
+	if err != nil || held != 3 {
+		t.Fatalf("CountHeldBack = %d, %v; want 3", held, err)
+	}
+
+	// Resting a selected thought clears it away without letting another in today.
+	if err := st.RestThought(first[0], nil, nil); err != nil {
+		t.Fatalf("rest #%d: %v", first[0], err)
+	}
+	if got := tendIDs(t, st); !slices.Equal(got, first[1:]) {
+		t.Errorf("after resting #%d offered %v, want %v", first[0], got, first[1:])
+	}
+	held, err = st.CountHeldBack()
+	if err != nil || held != 3 {
+		t.Errorf("CountHeldBack after rest = %d, %v; want 3", held, err)
+	}
+
+	for id := int64(1); id <= 5; id++ {
+		if slices.Contains(first, id) {
+			continue
+		}
+		if _, _, err := st.GetTendThought(id); err == nil {
+			t.Errorf("GetTendThought(%d) succeeded for a thought held back by the budget", id)
+		}
+	}
+}
+
+func TestDailySelectionReadsWhileAnotherPeonyWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peony.db")
+	st := openStoreAt(t, path)
+	prevBudget := core.DailyBudget
+	core.DailyBudget = 2
+	t.Cleanup(func() { core.DailyBudget = prevBudget })
+
+	for i := 0; i < 3; i++ {
+		addRipeThought(t, st, fmt.Sprintf("thought %d", i))
+	}
+	first := tendIDs(t, st)
+
+	// Once today's selection is pinned, reading it must not wait for another writer.
+	other := openStoreAt(t, path)
+	tx, err := other.beginTx()
+	if err != nil {
+		t.Fatalf("begin the other peony's write: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	start := time.Now()
+	if again := tendIDs(t, st); !slices.Equal(first, again) {
+		t.Errorf("selection changed while another peony wrote: %v then %v", first, again)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("reading the pinned selection waited %s for the other writer", waited)
+	}
+}
@@ -0,0 +1,11 @@
+package storage
+
+import (
+	"database/sql"
+)
+
+// beginTx starts a write transaction. Because the database is opened with immediate transactions,
+// it waits for other writers up front rather than failing midway.
+func (s *Store) beginTx() (*sql.Tx, error) {
+	return s.db.Begin()
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// ConflictError reports that a thought was saved by someone else after the caller read it,
+// for example from another terminal while an editor was open.
+type ConflictError struct {
+	ThoughtID int64
+	// Current is the thought as it is saved now: content, feeling, tags, state and updated_at.
+	Current core.Thought
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("#%d was changed elsewhere at %s", e.ThoughtID, e.Current.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+}
+
+// checkUnchangedTx fails with a *ConflictError when the thought's updated_at is no longer
+// expected, the value the caller read before making its changes. A zero expected skips the check.
+func checkUnchangedTx(tx *sql.Tx, id int64, expected time.Time) error {
+	if expected.IsZero() {
+		return nil
+	}
+
+	var (
+		current      = core.Thought{ID: id}
+		stateStr     string
+		updatedAtStr string
+		valence      sql.NullInt64
+		energy       sql.NullInt64
+	)
+	err := tx.QueryRow(
+		`SELECT uid, content, current_state, updated_at, valence, energy FROM thoughts WHERE id = ?`,
+		id,
+	).Scan(&current.UID, &current.Content, &stateStr, &updatedAtStr, &valence, &energy)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("not found (id=%d)", id)
+		}
+		return fmt.Errorf("read thought: %w", err)
+	}
+
+	current.UpdatedAt, err = parseTime(updatedAtStr)
+	if err != nil {
+		return fmt.Errorf("parse updated_at: %w", err)
+	}
+	if current.UpdatedAt.Equal(expected) {
+		return nil
+	}
+
+	current.CurrentState = core.State(stateStr)
+	if valence.Valid {
+		v := int(valence.Int64)
+		current.Valence = &v
+	}
+	if energy.Valid {
+		e := int(energy.Int64)
+		current.Energy = &e
+	}
+	current.Tags, err = readTags(tx, id)
+	if err != nil {
+		return err
+	}
+	return &ConflictError{ThoughtID: id, Current: current}
+}
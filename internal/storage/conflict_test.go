@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+func TestCommitTendRejectsAStaleRead(t *testing.T) {
+	st := newTestStore(t)
+	id := addRipeThought(t, st, "first words")
+	read, _, err := st.GetThought(id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	// Another terminal saves while the editor is open.
+	if err := st.UpdateThoughtContent(id, "saved elsewhere", read.UpdatedAt); err != nil {
+		t.Fatalf("update elsewhere: %v", err)
+	}
+
+	err = st.CommitTend(id, TendDecisions{Content: "my edit", Expected: read.UpdatedAt})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("CommitTend with a stale read = %v, want *ConflictError", err)
+	}
+	if conflict.ThoughtID != id || conflict.Current.Content != "saved elsewhere" || conflict.Current.CurrentState != core.StateCaptured {
+		t.Errorf("conflict = %+v, want the thought as saved elsewhere", conflict)
+	}
+
+	current, _, err := st.GetThought(id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if current.Content != "saved elsewhere" {
+		t.Errorf("content after the rejected commit = %q, want it untouched", current.Content)
+	}
+
+	// Saving against the version the conflict reported goes through.
+	if err := st.CommitTend(id, TendDecisions{Content: "my edit", Expected: conflict.Current.UpdatedAt}); err != nil {
+		t.Fatalf("CommitTend against the current version: %v", err)
+	}
+}
+
+func TestConflictReportsAStateChange(t *testing.T) {
+	st := newTestStore(t)
+	id := addRipeThought(t, st, "unchanged words")
+	read, _, err := st.GetThought(id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if err := st.ReleaseThought(id, nil); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	err = st.UpdateThoughtContent(id, "unchanged words, edited", read.UpdatedAt)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("UpdateThoughtContent after a release = %v, want *ConflictError", err)
+	}
+	if conflict.Current.CurrentState != core.StateReleased || conflict.Current.Content != read.Content {
+		t.Errorf("conflict = %+v, want the released thought with its content unchanged", conflict.Current)
+	}
+}
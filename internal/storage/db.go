@@ -9,6 +9,12 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+const (
+	// busyTimeoutMillis is how long a connection waits for another writer before giving up.
+	busyTimeoutMillis = "5000"
+	maxOpenConns      = 4
+)
+
 // DefaultDBPath returns the default filesystem location for Peony's SQLite database.
 func DefaultDBPath() (string, error) {
 	home, err := os.UserHomeDir()
@@ -38,12 +44,24 @@ func Open(dbPath string) (*sql.DB, error) {
 		return nil, fmt.Errorf("open: create db dir: %w", err)
 	}
 
-	dsn := "file:" + dbPath + "?mode=rwc&_pragma=foreign_keys(1)"
+	// Several terminals may use the same garden at once. WAL lets readers carry on while one
+	// connection writes, the busy timeout makes a writer wait its turn instead of failing with
+	// "database is locked", and immediate transactions take the write lock when they begin so
+	// they never have to upgrade (and fail) halfway through.
+	dsn := "file:" + dbPath + "?mode=rwc" +
+		"&_pragma=busy_timeout(" + busyTimeoutMillis + ")" +
+		"&_pragma=journal_mode(WAL)" +
+		"&_pragma=synchronous(NORMAL)" +
+		"&_pragma=foreign_keys(1)" +
+		"&_txlock=immediate"
 
 	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("open: sql open: %w", err)
 	}
+	// One invocation needs few connections; keeping them open avoids repeating the pragmas.
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxOpenConns)
 
 	err = db.Ping()
 	if err != nil {
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// MigrationStep describes one step of the schema migrator.
+type MigrationStep struct {
+	Version int
+	Name    string
+	Risky   bool
+}
+
+// AppliedMigration is a step the database has already been through. AppliedAt is nil for steps
+// applied before each step was recorded on its own.
+type AppliedMigration struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// SchemaStatus describes where a database stands against the migrations this peony knows.
+type SchemaStatus struct {
+	Path    string
+	Exists  bool
+	Current int
+	Latest  int
+	Applied []AppliedMigration
+	Pending []MigrationStep
+	// Backups lists the pre-migration backups found next to the database, oldest first.
+	Backups []string
+}
+
+// TooNew reports whether the database was migrated by a newer peony.
+func (s SchemaStatus) TooNew() bool {
+	return s.Current > s.Latest
+}
+
+// Inspect reports the schema status of the database at dbPath without creating or migrating it.
+func Inspect(dbPath string) (SchemaStatus, error) {
+	if dbPath == "" {
+		return SchemaStatus{}, fmt.Errorf("inspect: empty db path")
+	}
+
+	status := SchemaStatus{Path: dbPath, Latest: SchemaVersion}
+
+	if _, err := os.Stat(dbPath); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return SchemaStatus{}, fmt.Errorf("inspect: stat: %w", err)
+		}
+		status.Pending = pendingMigrations(0)
+		return status, nil
+	}
+	status.Exists = true
+
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro&_pragma=busy_timeout("+busyTimeoutMillis+")")
+	if err != nil {
+		return SchemaStatus{}, fmt.Errorf("inspect: sql open: %w", err)
+	}
+	defer db.Close()
+
+	recorded, err := recordedMigrations(db)
+	if err != nil {
+		return SchemaStatus{}, fmt.Errorf("inspect: %w", err)
+	}
+	for version := range recorded {
+		status.Current = max(status.Current, version)
+	}
+
+	// Steps at or below the current version were applied, recorded or not.
+	for _, step := range migrations {
+		if step.version > status.Current {
+			continue
+		}
+		applied := AppliedMigration{Version: step.version, Name: step.name}
+		if row, ok := recorded[step.version]; ok {
+			applied.AppliedAt = row.AppliedAt
+		}
+		status.Applied = append(status.Applied, applied)
+	}
+	// Steps from a newer peony are only known by what they recorded.
+	for version, row := range recorded {
+		if version > SchemaVersion {
+			status.Applied = append(status.Applied, row)
+		}
+	}
+	sort.Slice(status.Applied, func(i, j int) bool { return status.Applied[i].Version < status.Applied[j].Version })
+
+	status.Pending = pendingMigrations(status.Current)
+
+	status.Backups, err = filepath.Glob(dbPath + ".pre-v*.bak")
+	if err != nil {
+		return SchemaStatus{}, fmt.Errorf("inspect: list backups: %w", err)
+	}
+	sort.Strings(status.Backups)
+	return status, nil
+}
+
+// pendingMigrations returns the steps above current, in order.
+func pendingMigrations(current int) []MigrationStep {
+	pending := make([]MigrationStep, 0)
+	for _, step := range migrations {
+		if step.version > current {
+			pending = append(pending, MigrationStep{Version: step.version, Name: step.name, Risky: step.risky})
+		}
+	}
+	return pending
+}
+
+// recordedMigrations reads schema_migrations, tolerating tables from before names and times were kept.
+func recordedMigrations(db *sql.DB) (map[int]AppliedMigration, error) {
+	recorded := make(map[int]AppliedMigration)
+
+	columns, err := tableColumns(db, "schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations columns: %w", err)
+	}
+	if !columns["version"] {
+		return recorded, nil
+	}
+
+	query := `SELECT version, '', NULL FROM schema_migrations`
+	if columns["name"] && columns["applied_at"] {
+		query = `SELECT version, name, applied_at FROM schema_migrations`
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			row          AppliedMigration
+			appliedAtStr sql.NullString
+		)
+		if err := rows.Scan(&row.Version, &row.Name, &appliedAtStr); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		if appliedAtStr.Valid {
+			appliedAt, err := parseTime(appliedAtStr.String)
+			if err != nil {
+				return nil, fmt.Errorf("parse applied_at: %w", err)
+			}
+			row.AppliedAt = &appliedAt
+		}
+		recorded[row.Version] = row
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows schema_migrations: %w", err)
+	}
+	return recorded, nil
+}
+
+// backupDatabase writes a consistent copy of db next to its file, named after label, and returns
+// its path. An in-memory database has no file and is not backed up.
+func backupDatabase(db *sql.DB, label string) (string, error) {
+	var file string
+	err := db.QueryRow(`SELECT file FROM pragma_database_list WHERE name = 'main';`).Scan(&file)
+	if err != nil {
+		return "", fmt.Errorf("locate database file: %w", err)
+	}
+	if file == "" {
+		return "", nil
+	}
+
+	path := fmt.Sprintf("%s.%s-%s.bak", file, label, time.Now().UTC().Format("20060102T150405Z"))
+	if _, err := db.Exec(`VACUUM INTO ?;`, path); err != nil {
+		// Another peony opening the same database took this very backup a moment ago.
+		if _, statErr := os.Stat(path); statErr == nil {
+			return path, nil
+		}
+		return "", fmt.Errorf("vacuum into %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// installEventGuardsTx makes the events table append-only. Updates are always rejected; deletes
+// are only allowed for a thought that purgeTx has opened in purge_gate within the same transaction.
+// Triggers belong to their table, so they must be installed again if events is ever rebuilt.
+func installEventGuardsTx(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TRIGGER IF NOT EXISTS events_no_update
+		BEFORE UPDATE ON events
+		BEGIN
+			SELECT RAISE(ABORT, 'events are append-only');
+		END;
+	`)
+	if err != nil {
+		return fmt.Errorf("create events_no_update: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		CREATE TRIGGER IF NOT EXISTS events_no_delete
+		BEFORE DELETE ON events
+		WHEN NOT EXISTS (SELECT 1 FROM purge_gate WHERE thought_id = OLD.thought_id)
+		BEGIN
+			SELECT RAISE(ABORT, 'events are append-only; only purge may remove them');
+		END;
+	`)
+	if err != nil {
+		return fmt.Errorf("create events_no_delete: %w", err)
+	}
+	return nil
+}
+
+// installPurgeGateGuardsTx limits what purge_gate can open: only a released thought, and only one
+// at a time. Together with purgeEventsTx refusing to start while the gate holds a row, this keeps
+// a stray write to purge_gate from unlocking the history of thoughts that are still kept.
+func installPurgeGateGuardsTx(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TRIGGER IF NOT EXISTS purge_gate_released_only
+		BEFORE INSERT ON purge_gate
+		WHEN NOT EXISTS (SELECT 1 FROM thoughts WHERE id = NEW.thought_id AND current_state = 'released')
+		BEGIN
+			SELECT RAISE(ABORT, 'only a released thought can be purged');
+		END;
+	`)
+	if err != nil {
+		return fmt.Errorf("create purge_gate_released_only: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		CREATE TRIGGER IF NOT EXISTS purge_gate_one_at_a_time
+		BEFORE INSERT ON purge_gate
+		WHEN EXISTS (SELECT 1 FROM purge_gate)
+		BEGIN
+			SELECT RAISE(ABORT, 'the purge gate is already open');
+		END;
+	`)
+	if err != nil {
+		return fmt.Errorf("create purge_gate_one_at_a_time: %w", err)
+	}
+	return nil
+}
+
+// purgeGateIsClosed fails unless purge_gate is empty, as it must be outside purgeEventsTx.
+func purgeGateIsClosed(tx *sql.Tx) error {
+	var open int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM purge_gate`).Scan(&open); err != nil {
+		return fmt.Errorf("read purge gate: %w", err)
+	}
+	if open != 0 {
+		return fmt.Errorf("the purge gate was left open by another writer; refusing to purge")
+	}
+	return nil
+}
+
+// purgeEventsTx removes a thought's events through the purge gate and records the purge in
+// purge_log. The gate must be closed on entry and is closed again before returning, so it never
+// outlives the transaction.
+func purgeEventsTx(tx *sql.Tx, id int64, at string) error {
+	var uid string
+	if err := tx.QueryRow(`SELECT uid FROM thoughts WHERE id = ?`, id).Scan(&uid); err != nil {
+		return fmt.Errorf("read uid: %w", err)
+	}
+
+	if err := purgeGateIsClosed(tx); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`INSERT INTO purge_gate (thought_id) VALUES (?)`, id)
+	if err != nil {
+		return fmt.Errorf("open purge gate: %w", err)
+	}
+
+	res, err := tx.Exec(`DELETE FROM events WHERE thought_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete events: %w", err)
+	}
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	_, err = tx.Exec(`DELETE FROM purge_gate WHERE thought_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("close purge gate: %w", err)
+	}
+	if err := purgeGateIsClosed(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO purge_log (thought_uid, events_removed, purged_at) VALUES (?, ?, ?)`,
+		uid,
+		removed,
+		at,
+	)
+	if err != nil {
+		return fmt.Errorf("log purge: %w", err)
+	}
+	return nil
+}
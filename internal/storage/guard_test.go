@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"testing"
+)
+
+// eventRow is an events row as stored, for comparing history before and after a purge.
+type eventRow struct {
+	id      int64
+	thought int64
+	kind    string
+	at      string
+}
+
+func allEvents(t *testing.T, st *Store) []eventRow {
+	t.Helper()
+	rows, err := st.db.Query(`SELECT id, thought_id, kind, at FROM events ORDER BY id ASC`)
+	if err != nil {
+		t.Fatalf("query events: %v", err)
+	}
+	defer rows.Close()
+
+	events := make([]eventRow, 0)
+	for rows.Next() {
+		var e eventRow
+		if err := rows.Scan(&e.id, &e.thought, &e.kind, &e.at); err != nil {
+			t.Fatalf("scan event: %v", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("events rows: %v", err)
+	}
+	return events
+}
+
+func TestPurgeLeavesOtherEventsUntouched(t *testing.T) {
+	st := newTestStore(t)
+	keep := addRipeThought(t, st, "keep me")
+	gone := addRipeThought(t, st, "let me go")
+	last := addRipeThought(t, st, "keep me too")
+	if err := st.RestThought(keep, nil, nil); err != nil {
+		t.Fatalf("rest: %v", err)
+	}
+	if err := st.ReleaseThought(gone, nil); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	before := allEvents(t, st)
+	if err := st.PurgeThought(gone); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	after := allEvents(t, st)
+
+	want := make([]eventRow, 0, len(before))
+	for _, e := range before {
+		if e.thought != gone {
+			want = append(want, e)
+		}
+	}
+	if len(after) != len(want) {
+		t.Fatalf("after purge %d events remain, want %d", len(after), len(want))
+	}
+	for i := range want {
+		if after[i] != want[i] {
+			t.Errorf("event %d changed by purge: %+v, want %+v", i, after[i], want[i])
+		}
+	}
+
+	// Display IDs are not reused or renumbered.
+	if _, _, err := st.GetThought(last); err != nil {
+		t.Errorf("GetThought(%d) after purge: %v", last, err)
+	}
+	if _, _, err := st.GetThought(gone); err == nil {
+		t.Errorf("GetThought(%d) found the purged thought", gone)
+	}
+}
+
+func TestEventsAreAppendOnly(t *testing.T) {
+	st := newTestStore(t)
+	id := addRipeThought(t, st, "a thought")
+
+	if _, err := st.db.Exec(`UPDATE events SET note = 'rewritten' WHERE thought_id = ?`, id); err == nil {
+		t.Error("updating an event succeeded")
+	}
+	if _, err := st.db.Exec(`DELETE FROM events WHERE thought_id = ?`, id); err == nil {
+		t.Error("deleting an event outside a purge succeeded")
+	}
+}
+
+func TestPurgeGateOnlyOpensForReleasedThoughts(t *testing.T) {
+	st := newTestStore(t)
+	kept := addRipeThought(t, st, "still here")
+	released := addRipeThought(t, st, "released")
+	other := addRipeThought(t, st, "released too")
+	for _, id := range []int64{released, other} {
+		if err := st.ReleaseThought(id, nil); err != nil {
+			t.Fatalf("release #%d: %v", id, err)
+		}
+	}
+
+	if _, err := st.db.Exec(`INSERT INTO purge_gate (thought_id) VALUES (?)`, kept); err == nil {
+		t.Error("opened purge_gate for a thought that is not released")
+	}
+	if err := st.PurgeThought(kept); err == nil {
+		t.Error("purged a thought that is not released")
+	}
+
+	tx, err := st.db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := tx.Exec(`INSERT INTO purge_gate (thought_id) VALUES (?)`, released); err != nil {
+		t.Fatalf("open purge_gate for a released thought: %v", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO purge_gate (thought_id) VALUES (?)`, other); err == nil {
+		t.Error("opened purge_gate for a second thought at once")
+	}
+}
@@ -2,45 +2,200 @@ package storage
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 )
 
+// migration is one numbered, ordered change to the schema. Each is applied in its own
+// transaction and recorded in schema_migrations when it commits.
+type migration struct {
+	version int
+	name    string
+	// risky marks steps that rewrite existing data; a backup is taken before they run.
+	risky bool
+	apply func(tx *sql.Tx) error
+}
+
+// migrations lists every schema step in order. Never edit or renumber a released step;
+// append a new one instead. The first step is numbered 2, the version the original schema
+// was recorded as.
+var migrations = []migration{
+	{version: 2, name: "create thoughts, events and app_state", apply: migrateBaseSchema},
+	{version: 3, name: "give every thought a permanent UID", risky: true, apply: migrateThoughtUIDs},
+	{version: 4, name: "record eligibility and detail on events", apply: migrateEventDetail},
+	{version: 5, name: "keep every revision of a thought's content", risky: true, apply: migrateRevisions},
+	{version: 6, name: "make events append-only", apply: migrateAppendOnlyEvents},
+	{version: 7, name: "store timestamps at a fixed width", risky: true, apply: migrateFixedWidthTimes},
+	{version: 8, name: "let thoughts carry tags", apply: migrateThoughtTags},
+}
+
 // SchemaVersion is the latest schema version supported by the migrator.
-const SchemaVersion = 2
+var SchemaVersion = migrations[len(migrations)-1].version
+
+// ErrSchemaTooNew is returned when the database was migrated by a newer peony than this one.
+var ErrSchemaTooNew = errors.New("database was written by a newer peony; please upgrade peony")
 
-// Migrate ensures the SQLite schema exists and is upgraded to SchemaVersion.
+// Migrate ensures the SQLite schema exists and is upgraded to SchemaVersion, one step at a time.
+// Before the first risky step on an existing database it saves a backup next to the database file.
 func Migrate(db *sql.DB) error {
 	if db == nil {
 		return fmt.Errorf("migrate: db is nil")
 	}
 
-	// err holds the error returned by each DDL/DML operation during migration.
-	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY);`)
-	if err != nil {
-		return fmt.Errorf("migrate: create schema_migrations: %w", err)
+	if err := ensureMigrationsTable(db); err != nil {
+		return fmt.Errorf("migrate: %w", err)
 	}
 
 	// current is the highest schema version recorded in schema_migrations.
-	var current int
-	err = db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations;`).Scan(&current)
+	current, err := currentSchemaVersion(db)
 	if err != nil {
-		return fmt.Errorf("migrate: read current version: %w", err)
+		return fmt.Errorf("migrate: %w", err)
+	}
+	if current > SchemaVersion {
+		return fmt.Errorf("migrate: schema version %d, this peony knows up to %d: %w", current, SchemaVersion, ErrSchemaTooNew)
 	}
 
-	if current >= SchemaVersion {
+	backedUp := false
+	for _, step := range migrations {
+		if step.version <= current {
+			continue
+		}
+
+		if step.risky && current > 0 && !backedUp {
+			// Another peony may have migrated the database since it was first read.
+			latest, err := currentSchemaVersion(db)
+			if err != nil {
+				return fmt.Errorf("migrate: %w", err)
+			}
+			if latest >= step.version {
+				continue
+			}
+			if _, err := backupDatabase(db, fmt.Sprintf("pre-v%d", step.version)); err != nil {
+				return fmt.Errorf("migrate: backup before v%d: %w", step.version, err)
+			}
+			backedUp = true
+		}
+
+		if err := applyMigration(db, step); err != nil {
+			return fmt.Errorf("migrate: v%d %s: %w", step.version, step.name, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one step and records it in a single transaction. The transaction is
+// immediate, so when two peonies open an outdated database at once the second waits, sees the
+// step already recorded and leaves it be.
+func applyMigration(db *sql.DB, step migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	current, err := currentSchemaVersion(tx)
+	if err != nil {
+		return err
+	}
+	if current >= step.version {
 		return nil
 	}
 
-	// transaction groups schema changes so the migration is applied atomically.
-	transaction, err := db.Begin()
+	if err := step.apply(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?);`,
+		step.version,
+		step.name,
+		formatTime(time.Now()),
+	)
+	if err != nil {
+		return fmt.Errorf("record version: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ensureMigrationsTable creates schema_migrations, adding the name and applied_at columns to
+// tables created before steps were recorded individually. Older rows keep an unknown applied_at.
+func ensureMigrationsTable(db *sql.DB) error {
+	tx, err := db.Begin()
 	if err != nil {
-		return fmt.Errorf("migrate: begin transaction: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
 	defer func() {
-		_ = transaction.Rollback()
+		_ = tx.Rollback()
 	}()
 
-	_, err = transaction.Exec(`
+	_, err = tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY);`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	columns, err := tableColumns(tx, "schema_migrations")
+	if err != nil {
+		return fmt.Errorf("read schema_migrations columns: %w", err)
+	}
+	if !columns["name"] {
+		if _, err := tx.Exec(`ALTER TABLE schema_migrations ADD COLUMN name TEXT NOT NULL DEFAULT '';`); err != nil {
+			return fmt.Errorf("add schema_migrations.name: %w", err)
+		}
+	}
+	if !columns["applied_at"] {
+		if _, err := tx.Exec(`ALTER TABLE schema_migrations ADD COLUMN applied_at TEXT NULL;`); err != nil {
+			return fmt.Errorf("add schema_migrations.applied_at: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// currentSchemaVersion returns the highest version recorded in schema_migrations, or 0 for a new database.
+func currentSchemaVersion(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (int, error) {
+	var current int
+	err := q.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations;`).Scan(&current)
+	if err != nil {
+		return 0, fmt.Errorf("read current version: %w", err)
+	}
+	return current, nil
+}
+
+// tableColumns returns the set of column names in table.
+func tableColumns(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, table string) (map[string]bool, error) {
+	rows, err := q.Query(`SELECT name FROM pragma_table_info(?);`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+func migrateBaseSchema(tx *sql.Tx) error {
+	_, err := tx.Exec(`
 		CREATE TABLE IF NOT EXISTS thoughts (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			content TEXT NOT NULL,
@@ -55,10 +210,10 @@ func Migrate(db *sql.DB) error {
 		);
 	`)
 	if err != nil {
-		return fmt.Errorf("migrate: create thoughts table: %w", err)
+		return fmt.Errorf("create thoughts table: %w", err)
 	}
 
-	_, err = transaction.Exec(`
+	_, err = tx.Exec(`
 		CREATE TABLE IF NOT EXISTS events (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			thought_id INTEGER NOT NULL,
@@ -71,10 +226,10 @@ func Migrate(db *sql.DB) error {
 		);
 	`)
 	if err != nil {
-		return fmt.Errorf("migrate: create events table: %w", err)
+		return fmt.Errorf("create events table: %w", err)
 	}
 
-	_, err = transaction.Exec(`
+	_, err = tx.Exec(`
 		CREATE TABLE IF NOT EXISTS app_state (
 			key TEXT PRIMARY KEY,
 			value TEXT NOT NULL,
@@ -82,28 +237,271 @@ func Migrate(db *sql.DB) error {
 		);
 	`)
 	if err != nil {
-		return fmt.Errorf("migrate: create app_state table: %w", err)
+		return fmt.Errorf("create app_state table: %w", err)
 	}
 
-	_, err = transaction.Exec(`CREATE INDEX IF NOT EXISTS idx_thoughts_state_eligibility ON thoughts(current_state, eligibility_at);`)
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_thoughts_state_eligibility ON thoughts(current_state, eligibility_at);`)
 	if err != nil {
-		return fmt.Errorf("migrate: create idx_thoughts_state_eligibility: %w", err)
+		return fmt.Errorf("create idx_thoughts_state_eligibility: %w", err)
 	}
 
-	_, err = transaction.Exec(`CREATE INDEX IF NOT EXISTS idx_events_thought_id_at ON events(thought_id, at);`)
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_events_thought_id_at ON events(thought_id, at);`)
 	if err != nil {
-		return fmt.Errorf("migrate: create idx_events_thought_id_at: %w", err)
+		return fmt.Errorf("create idx_events_thought_id_at: %w", err)
 	}
+	return nil
+}
 
-	_, err = transaction.Exec(`INSERT INTO schema_migrations(version) VALUES (?);`, SchemaVersion)
+// migrateThoughtUIDs gives every thought a permanent UID that survives display ID reindexing.
+func migrateThoughtUIDs(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE thoughts ADD COLUMN uid TEXT NOT NULL DEFAULT '';`)
 	if err != nil {
-		return fmt.Errorf("migrate: record schema version: %w", err)
+		return fmt.Errorf("add thoughts.uid: %w", err)
 	}
 
-	err = transaction.Commit()
+	err = backfillThoughtUIDs(tx)
 	if err != nil {
-		return fmt.Errorf("migrate: commit transaction: %w", err)
+		return fmt.Errorf("backfill thoughts.uid: %w", err)
 	}
 
+	_, err = tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_thoughts_uid ON thoughts(uid);`)
+	if err != nil {
+		return fmt.Errorf("create idx_thoughts_uid: %w", err)
+	}
+	return nil
+}
+
+// migrateEventDetail lets events record the eligibility they set and why it was chosen.
+func migrateEventDetail(tx *sql.Tx) error {
+	_, err := tx.Exec(`ALTER TABLE events ADD COLUMN eligibility_at TEXT NULL;`)
+	if err != nil {
+		return fmt.Errorf("add events.eligibility_at: %w", err)
+	}
+
+	_, err = tx.Exec(`ALTER TABLE events ADD COLUMN detail TEXT NULL;`)
+	if err != nil {
+		return fmt.Errorf("add events.detail: %w", err)
+	}
+	return nil
+}
+
+// migrateRevisions keeps every version of a thought's content. Existing thoughts start with their
+// current content as revision 1, linked to their first event, or to none for a thought whose
+// events are missing; earlier edits were not kept.
+func migrateRevisions(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			thought_id INTEGER NOT NULL,
+			event_id INTEGER NULL,
+			number INTEGER NOT NULL,
+			content TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			FOREIGN KEY(thought_id) REFERENCES thoughts(id),
+			FOREIGN KEY(event_id) REFERENCES events(id)
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("create revisions table: %w", err)
+	}
+
+	_, err = tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_revisions_thought_number ON revisions(thought_id, number);`)
+	if err != nil {
+		return fmt.Errorf("create idx_revisions_thought_number: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO revisions (thought_id, event_id, number, content, created_at)
+		SELECT t.id, MIN(e.id), 1, t.content, t.created_at
+		FROM thoughts t
+		LEFT JOIN events e ON e.thought_id = t.id
+		GROUP BY t.id
+		ORDER BY t.id;
+	`)
+	if err != nil {
+		return fmt.Errorf("backfill revisions: %w", err)
+	}
+	return nil
+}
+
+// migrateAppendOnlyEvents makes events append-only in the database itself. Only purge may remove
+// them, by opening purge_gate for one released thought at a time, and each purge is kept in purge_log.
+func migrateAppendOnlyEvents(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS purge_gate (
+			thought_id INTEGER PRIMARY KEY
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("create purge_gate table: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		CREATE TABLE IF NOT EXISTS purge_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			thought_uid TEXT NOT NULL,
+			events_removed INTEGER NOT NULL,
+			purged_at TEXT NOT NULL
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("create purge_log table: %w", err)
+	}
+
+	if err := installEventGuardsTx(tx); err != nil {
+		return err
+	}
+	return installPurgeGateGuardsTx(tx)
+}
+
+// migrateThoughtTags adds the thought_tags table; each row attaches one tag to one thought.
+func migrateThoughtTags(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS thought_tags (
+			thought_id INTEGER NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (thought_id, tag),
+			FOREIGN KEY(thought_id) REFERENCES thoughts(id)
+		);
+	`)
+	if err != nil {
+		return fmt.Errorf("create thought_tags table: %w", err)
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_thought_tags_tag ON thought_tags(tag);`)
+	if err != nil {
+		return fmt.Errorf("create idx_thought_tags_tag: %w", err)
+	}
+	return nil
+}
+
+// timestampColumns lists every column that holds a timestamp, with an optional row filter.
+var timestampColumns = []struct {
+	table, column, where string
+}{
+	{"thoughts", "created_at", ""},
+	{"thoughts", "updated_at", ""},
+	{"thoughts", "last_tended_at", ""},
+	{"thoughts", "eligibility_at", ""},
+	{"events", "at", ""},
+	{"events", "eligibility_at", ""},
+	{"revisions", "created_at", ""},
+	{"app_state", "updated_at", ""},
+	{"app_state", "value", "key = '" + appStateKeyPausedAt + "'"},
+	{"purge_log", "purged_at", ""},
+	{"schema_migrations", "applied_at", ""},
+}
+
+// migrateFixedWidthTimes rewrites every stored timestamp in timeLayout so that text comparisons
+// in SQL order them correctly, and indexes the columns that date filters range over. The moments
+// themselves are unchanged, so the append-only guard on events is lifted only for this step.
+func migrateFixedWidthTimes(tx *sql.Tx) error {
+	for _, trigger := range []string{"events_no_update", "events_no_delete"} {
+		if _, err := tx.Exec(`DROP TRIGGER IF EXISTS ` + trigger + `;`); err != nil {
+			return fmt.Errorf("drop %s: %w", trigger, err)
+		}
+	}
+
+	for _, c := range timestampColumns {
+		if err := reformatTimesTx(tx, c.table, c.column, c.where); err != nil {
+			return fmt.Errorf("reformat %s.%s: %w", c.table, c.column, err)
+		}
+	}
+
+	if err := installEventGuardsTx(tx); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_thoughts_created_at ON thoughts(created_at);`)
+	if err != nil {
+		return fmt.Errorf("create idx_thoughts_created_at: %w", err)
+	}
+
+	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_events_at ON events(at);`)
+	if err != nil {
+		return fmt.Errorf("create idx_events_at: %w", err)
+	}
+	return nil
+}
+
+// reformatTimesTx rewrites the non-null values of table.column in timeLayout.
+func reformatTimesTx(tx *sql.Tx, table, column, where string) error {
+	query := `SELECT rowid, ` + column + ` FROM ` + table + ` WHERE ` + column + ` IS NOT NULL`
+	if where != "" {
+		query += ` AND ` + where
+	}
+	rows, err := tx.Query(query + `;`)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	type stamp struct {
+		rowid int64
+		value string
+	}
+	stamps := make([]stamp, 0)
+	for rows.Next() {
+		var st stamp
+		if err := rows.Scan(&st.rowid, &st.value); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan: %w", err)
+		}
+		stamps = append(stamps, st)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return fmt.Errorf("rows: %w", err)
+	}
+	_ = rows.Close()
+
+	for _, st := range stamps {
+		t, err := parseTime(st.value)
+		if err != nil {
+			return fmt.Errorf("parse rowid=%d: %w", st.rowid, err)
+		}
+		formatted := formatTime(t)
+		if formatted == st.value {
+			continue
+		}
+		_, err = tx.Exec(`UPDATE `+table+` SET `+column+` = ? WHERE rowid = ?;`, formatted, st.rowid)
+		if err != nil {
+			return fmt.Errorf("update rowid=%d: %w", st.rowid, err)
+		}
+	}
+	return nil
+}
+
+// backfillThoughtUIDs assigns a fresh UID to every thought that does not have one yet.
+func backfillThoughtUIDs(transaction *sql.Tx) error {
+	rows, err := transaction.Query(`SELECT id FROM thoughts WHERE uid = '' ORDER BY id ASC;`)
+	if err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return fmt.Errorf("rows: %w", err)
+	}
+	_ = rows.Close()
+
+	for _, id := range ids {
+		uid, err := newThoughtUID()
+		if err != nil {
+			return err
+		}
+		_, err = transaction.Exec(`UPDATE thoughts SET uid = ? WHERE id = ?;`, uid, id)
+		if err != nil {
+			return fmt.Errorf("update id=%d: %w", id, err)
+		}
+	}
 	return nil
 }
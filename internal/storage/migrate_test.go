@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMigrateFromBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peony.db")
+	seedBaselineDB(t, path, baselineGarden...)
+	st := openStoreAt(t, path)
+
+	version, err := currentSchemaVersion(st.db)
+	if err != nil || version != SchemaVersion {
+		t.Fatalf("schema version = %d, %v; want %d", version, err, SchemaVersion)
+	}
+	thought, _, err := st.GetThought(2)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if thought.UID == "" || thought.Content != "tended and rested" || thought.TendCounter != 1 {
+		t.Errorf("migrated thought = %+v", thought)
+	}
+}
+
+func TestMigrateGivesEveryThoughtARevision(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peony.db")
+	orphan := `INSERT INTO thoughts (id, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at)
+	 VALUES (3, 'lost its events', 'captured', 0, '2025-03-05T08:00:00Z', '2025-03-05T08:00:00Z', NULL, '2025-03-06T02:00:00Z')`
+	seedBaselineDB(t, path, append(slices.Clone(baselineGarden), orphan)...)
+	st := openStoreAt(t, path)
+
+	for _, id := range []int64{1, 2, 3} {
+		revisions, err := st.ListRevisions(id)
+		if err != nil {
+			t.Fatalf("#%d: %v", id, err)
+		}
+		if len(revisions) != 1 || revisions[0].Number != 1 {
+			t.Fatalf("#%d revisions = %+v, want revision 1 alone", id, revisions)
+		}
+		if hasEvent := revisions[0].EventID != nil; hasEvent != (id != 3) {
+			t.Errorf("#%d revision 1 event = %v, want one only when the thought has events", id, revisions[0].EventID)
+		}
+	}
+
+	revision, err := st.GetRevision(3, 1)
+	if err != nil {
+		t.Fatalf("get revision: %v", err)
+	}
+	if revision.Content != "lost its events" || revision.CreatedAt.UTC().Format(time.RFC3339) != "2025-03-05T08:00:00Z" {
+		t.Errorf("orphan revision = %+v, want the thought's content as of its creation", revision)
+	}
+}
+
+func TestConcurrentMigrationsApplyEachStepOnce(t *testing.T) {
+	for round := 0; round < 3; round++ {
+		path := filepath.Join(t.TempDir(), "peony.db")
+		seedBaselineDB(t, path, baselineGarden...)
+
+		const peonies = 4
+		var wg sync.WaitGroup
+		errs := make([]error, peonies)
+		for i := 0; i < peonies; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				db, err := Open(path)
+				if err == nil {
+					err = db.Close()
+				}
+				errs[i] = err
+			}()
+		}
+		wg.Wait()
+		for i, err := range errs {
+			if err != nil {
+				t.Fatalf("round %d: peony %d failed to open: %v", round, i, err)
+			}
+		}
+
+		st := openStoreAt(t, path)
+		var applied, distinct int
+		if err := st.db.QueryRow(`SELECT COUNT(*), COUNT(DISTINCT version) FROM schema_migrations`).Scan(&applied, &distinct); err != nil {
+			t.Fatalf("read schema_migrations: %v", err)
+		}
+		if applied != len(migrations) || distinct != len(migrations) {
+			t.Errorf("round %d: %d migrations recorded (%d distinct), want %d", round, applied, distinct, len(migrations))
+		}
+	}
+}
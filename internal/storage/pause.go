@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+const appStateKeyPausedAt = "paused_at"
+
+// ErrNotPaused is returned by ResumeRipening when Peony is not paused.
+var ErrNotPaused = errors.New("not paused")
+
+// PausedSince returns when ripening was paused, or nil when Peony is not paused.
+func (s *Store) PausedSince() (*time.Time, error) {
+	if s == nil {
+		return nil, fmt.Errorf("paused since: store is nil")
+	}
+	if s.db == nil {
+		return nil, fmt.Errorf("paused since: db is nil")
+	}
+
+	pausedAt, err := readPausedAt(s.db)
+	if err != nil {
+		return nil, fmt.Errorf("paused since: %w", err)
+	}
+	return pausedAt, nil
+}
+
+// readPausedAt returns the recorded pause, or nil when there is none.
+func readPausedAt(q interface {
+	QueryRow(query string, args ...any) *sql.Row
+}) (*time.Time, error) {
+	var value string
+	err := q.QueryRow(`SELECT value FROM app_state WHERE key = ?`, appStateKeyPausedAt).Scan(&value)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	pausedAt, err := parseTime(value)
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	return &pausedAt, nil
+}
+
+// PauseRipening records the moment Peony was paused. Thoughts keep their eligibility until
+// ResumeRipening shifts it forward by the time spent paused.
+func (s *Store) PauseRipening() (time.Time, error) {
+	if s == nil {
+		return time.Time{}, fmt.Errorf("pause: store is nil")
+	}
+	if s.db == nil {
+		return time.Time{}, fmt.Errorf("pause: db is nil")
+	}
+
+	tx, err := s.beginTx()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("pause: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pausedAt, err := readPausedAt(tx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("pause: %w", err)
+	}
+	if pausedAt != nil {
+		return *pausedAt, fmt.Errorf("pause: already paused since %s", pausedAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	now := time.Now().UTC()
+	nowStr := formatTime(now)
+	_, err = tx.Exec(
+		`INSERT INTO app_state(key, value, updated_at) VALUES (?, ?, ?)`,
+		appStateKeyPausedAt,
+		nowStr,
+		nowStr,
+	)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("pause: insert: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return time.Time{}, fmt.Errorf("pause: commit: %w", err)
+	}
+	return now, nil
+}
+
+// ResumeRipening ends a pause. Every captured or resting thought has its eligibility_at moved
+// forward by the paused interval, and each shift is recorded as an event. It returns the
+// interval and the number of thoughts shifted.
+//
+// The pause is read and cleared in the same immediate transaction as the shift, so two resumes
+// racing each other shift thoughts once; the later one finds no pause and returns ErrNotPaused.
+func (s *Store) ResumeRipening() (time.Duration, int, error) {
+	if s == nil {
+		return 0, 0, fmt.Errorf("resume: store is nil")
+	}
+	if s.db == nil {
+		return 0, 0, fmt.Errorf("resume: db is nil")
+	}
+
+	tx, err := s.beginTx()
+	if err != nil {
+		return 0, 0, fmt.Errorf("resume: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	pausedAt, err := readPausedAt(tx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("resume: %w", err)
+	}
+	if pausedAt == nil {
+		return 0, 0, fmt.Errorf("resume: %w", ErrNotPaused)
+	}
+
+	now := time.Now().UTC()
+	nowStr := formatTime(now)
+	interval := now.Sub(*pausedAt)
+	if interval < 0 {
+		interval = 0
+	}
+
+	rows, err := tx.Query(
+		`SELECT id, eligibility_at FROM thoughts WHERE current_state IN (?, ?) ORDER BY id ASC`,
+		string(core.StateCaptured),
+		string(core.StateResting),
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("resume: query thoughts: %w", err)
+	}
+
+	type shift struct {
+		id    int64
+		until time.Time
+	}
+	shifts := make([]shift, 0)
+	for rows.Next() {
+		var id int64
+		var eligibilityAtStr string
+		if err := rows.Scan(&id, &eligibilityAtStr); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("resume: scan: %w", err)
+		}
+		eligibilityAt, err := parseTime(eligibilityAtStr)
+		if err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("resume: parse eligibility_at: %w", err)
+		}
+		shifts = append(shifts, shift{id: id, until: eligibilityAt.Add(interval)})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, 0, fmt.Errorf("resume: rows: %w", err)
+	}
+	rows.Close()
+
+	detail := fmt.Sprintf("paused %s to %s; ripening shifted by %s",
+		pausedAt.Local().Format("2006-01-02 15:04"),
+		now.Local().Format("2006-01-02 15:04"),
+		core.FormatSpan(interval.Round(time.Minute)),
+	)
+	for _, sh := range shifts {
+		untilStr := formatTime(sh.until)
+		// Only eligibility moves; updated_at is left alone so the shift reads as no edit at all,
+		// and the shifted event below records it.
+		if _, err := tx.Exec(
+			`UPDATE thoughts SET eligibility_at = ? WHERE id = ?`,
+			untilStr,
+			sh.id,
+		); err != nil {
+			return 0, 0, fmt.Errorf("resume: shift thought %d: %w", sh.id, err)
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note, eligibility_at, detail)
+			 VALUES (?, ?, ?, NULL, NULL, NULL, ?, ?)`,
+			sh.id,
+			core.EventShifted,
+			nowStr,
+			untilStr,
+			detail,
+		); err != nil {
+			return 0, 0, fmt.Errorf("resume: insert event: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM app_state WHERE key = ?`, appStateKeyPausedAt); err != nil {
+		return 0, 0, fmt.Errorf("resume: clear pause: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("resume: commit: %w", err)
+	}
+	return interval, len(shifts), nil
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+func TestResumeShiftsOnlyOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peony.db")
+	// Two terminals resuming the same garden at once.
+	stores := make([]*Store, 2)
+	for i := range stores {
+		db, err := Open(path)
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+		if stores[i], err = New(db); err != nil {
+			t.Fatalf("new store: %v", err)
+		}
+	}
+	st := stores[0]
+	id := addRipeThought(t, st, "waiting")
+	before, _, err := st.GetThought(id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if _, err := st.PauseRipening(); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	pausedAt := formatTime(time.Now().Add(-2 * time.Hour))
+	if _, err := st.db.Exec(`UPDATE app_state SET value = ? WHERE key = ?`, pausedAt, appStateKeyPausedAt); err != nil {
+		t.Fatalf("backdate pause: %v", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		resumed  int
+		notPause int
+	)
+	for _, s := range stores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := s.ResumeRipening()
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				resumed++
+			case errors.Is(err, ErrNotPaused):
+				notPause++
+			default:
+				t.Errorf("resume: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if resumed != 1 || notPause != 1 {
+		t.Fatalf("resumed %d times and found no pause %d times, want once each", resumed, notPause)
+	}
+
+	after, events, err := st.GetThought(id)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	shifted := 0
+	for _, e := range events {
+		if e.Kind == core.EventShifted {
+			shifted++
+		}
+	}
+	if shifted != 1 {
+		t.Errorf("recorded %d shifted events, want 1", shifted)
+	}
+	if moved := after.EligibilityAt.Sub(before.EligibilityAt); moved < 2*time.Hour || moved > 2*time.Hour+time.Minute {
+		t.Errorf("eligibility moved by %s, want about 2h", moved)
+	}
+
+	if since, err := st.PausedSince(); err != nil || since != nil {
+		t.Errorf("PausedSince after resume = %v, %v; want not paused", since, err)
+	}
+
+	// The shift is not an edit: view order and anything open against the thought are unaffected.
+	if !after.UpdatedAt.Equal(before.UpdatedAt) {
+		t.Errorf("updated_at moved from %s to %s", before.UpdatedAt, after.UpdatedAt)
+	}
+	reports, err := st.RebuildSnapshots(id, false)
+	if err != nil || len(reports) != 0 {
+		t.Errorf("RebuildSnapshots after resume = %+v, %v; want no discrepancies", reports, err)
+	}
+}
+
+func TestConcurrentPauseReportsAlreadyPaused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peony.db")
+	stores := []*Store{openStoreAt(t, path), openStoreAt(t, path)}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(stores))
+	for i, s := range stores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = s.PauseRipening()
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		failed++
+		if !strings.Contains(err.Error(), "already paused") {
+			t.Errorf("second pause = %v, want an already paused error", err)
+		}
+	}
+	if failed != 1 {
+		t.Errorf("%d of 2 concurrent pauses failed, want exactly 1", failed)
+	}
+}
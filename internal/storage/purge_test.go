@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPurgeReleasedBeforeUsesTheReleaseTime(t *testing.T) {
+	st := newTestStore(t)
+	touchedLater := addRipeThought(t, st, "released, snapshot touched later")
+	touchedEarlier := addRipeThought(t, st, "released, snapshot dated long ago")
+	kept := addRipeThought(t, st, "still growing")
+	for _, id := range []int64{touchedLater, touchedEarlier} {
+		if err := st.ReleaseThought(id, nil); err != nil {
+			t.Fatalf("release #%d: %v", id, err)
+		}
+	}
+
+	// updated_at says nothing about when a thought was let go.
+	setUpdatedAt := func(id int64, at time.Time) {
+		t.Helper()
+		if _, err := st.db.Exec(`UPDATE thoughts SET updated_at = ? WHERE id = ?`, formatTime(at), id); err != nil {
+			t.Fatalf("set updated_at of #%d: %v", id, err)
+		}
+	}
+	setUpdatedAt(touchedLater, time.Now().Add(24*time.Hour))
+	setUpdatedAt(touchedEarlier, time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC))
+
+	n, err := st.PurgeReleasedBefore(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil || n != 0 {
+		t.Fatalf("PurgeReleasedBefore(2020) = %d, %v; want nothing purged", n, err)
+	}
+
+	n, err = st.PurgeReleasedBefore(time.Now().Add(time.Minute))
+	if err != nil || n != 2 {
+		t.Fatalf("PurgeReleasedBefore(now) = %d, %v; want 2", n, err)
+	}
+	if _, _, err := st.GetThought(kept); err != nil {
+		t.Errorf("GetThought(%d): %v", kept, err)
+	}
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// SnapshotReport describes how one thought's stored snapshot compares with its history.
+type SnapshotReport struct {
+	ThoughtID     int64
+	UID           string
+	Discrepancies []core.Discrepancy
+	// NoHistory is set when the thought has no events at all, so nothing can be derived.
+	NoHistory bool
+	Repaired  bool
+}
+
+// RebuildSnapshots re-derives each thought's state, tend count, last tended time and timestamps
+// from its events (and its content from its latest revision) and reports every thought whose
+// snapshot disagrees. With repair set, the disagreeing snapshots are overwritten in one
+// transaction. An id of 0 checks the whole garden.
+func (s *Store) RebuildSnapshots(id int64, repair bool) ([]SnapshotReport, error) {
+	if s == nil {
+		return nil, fmt.Errorf("rebuild snapshots: store is nil")
+	}
+	if s.db == nil {
+		return nil, fmt.Errorf("rebuild snapshots: db is nil")
+	}
+	if id < 0 {
+		return nil, fmt.Errorf("rebuild snapshots: invalid thought ID")
+	}
+
+	ids := []int64{id}
+	if id == 0 {
+		var err error
+		if ids, err = s.allThoughtIDs(); err != nil {
+			return nil, fmt.Errorf("rebuild snapshots: %w", err)
+		}
+	}
+
+	reports := make([]SnapshotReport, 0)
+	derivedByID := make(map[int64]core.Thought)
+	for _, thoughtID := range ids {
+		current, events, err := s.GetThought(thoughtID)
+		if err != nil {
+			return nil, fmt.Errorf("rebuild snapshots: %w", err)
+		}
+		if len(events) == 0 {
+			reports = append(reports, SnapshotReport{ThoughtID: thoughtID, UID: current.UID, NoHistory: true})
+			continue
+		}
+		revisions, err := s.revisionsFor(thoughtID)
+		if err != nil {
+			return nil, fmt.Errorf("rebuild snapshots: revisions: %w", err)
+		}
+
+		// Replay up to the last recorded event, however far in the future a clock skew put it.
+		derived, ok := core.Replay(current, events, revisions, events[len(events)-1].At.Add(time.Second))
+		if !ok {
+			reports = append(reports, SnapshotReport{ThoughtID: thoughtID, UID: current.UID, NoHistory: true})
+			continue
+		}
+		derived = core.KeepSnapshotTimes(current, derived)
+		diffs := core.CompareSnapshot(current, derived, len(revisions) > 0)
+		if len(diffs) == 0 {
+			continue
+		}
+		reports = append(reports, SnapshotReport{ThoughtID: thoughtID, UID: current.UID, Discrepancies: diffs})
+		derivedByID[thoughtID] = derived
+	}
+
+	if !repair || len(derivedByID) == 0 {
+		return reports, nil
+	}
+
+	tx, err := s.beginTx()
+	if err != nil {
+		return nil, fmt.Errorf("rebuild snapshots: begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for i := range reports {
+		derived, ok := derivedByID[reports[i].ThoughtID]
+		if !ok {
+			continue
+		}
+
+		var lastTendedAt any
+		if derived.LastTendedAt != nil {
+			lastTendedAt = formatTime(*derived.LastTendedAt)
+		}
+		// Histories from before eligibility was recorded keep the snapshot's value.
+		var eligibilityAt any
+		if !derived.EligibilityAt.IsZero() {
+			eligibilityAt = formatTime(derived.EligibilityAt)
+		}
+		_, err := tx.Exec(
+			`UPDATE thoughts
+			 SET content = ?, current_state = ?, tend_counter = ?, last_tended_at = ?, created_at = ?, updated_at = ?,
+			     eligibility_at = COALESCE(?, eligibility_at)
+			 WHERE id = ?`,
+			derived.Content,
+			string(derived.CurrentState),
+			derived.TendCounter,
+			lastTendedAt,
+			formatTime(derived.CreatedAt),
+			formatTime(derived.UpdatedAt),
+			eligibilityAt,
+			derived.ID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rebuild snapshots: update #%d: %w", derived.ID, err)
+		}
+		reports[i].Repaired = true
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("rebuild snapshots: commit: %w", err)
+	}
+	return reports, nil
+}
+
+// allThoughtIDs returns every thought ID in ascending order.
+func (s *Store) allThoughtIDs() ([]int64, error) {
+	rows, err := s.db.Query(`SELECT id FROM thoughts ORDER BY id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// baselineGarden is history as the first release wrote it: every thought's captured event was
+// appended in a separate step after the row was inserted, a millisecond or two later, while
+// tends and resolutions shared one timestamp between row and event.
+var baselineGarden = []string{
+	`INSERT INTO thoughts (id, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at)
+	 VALUES (1, 'only captured', 'captured', 0, '2025-03-01T10:00:00.123456789Z', '2025-03-01T10:00:00.123456789Z', NULL, '2025-03-02T04:00:00.123456789Z')`,
+	`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note)
+	 VALUES (1, 'captured', '2025-03-01T10:00:00.124901234Z', NULL, 'captured', NULL)`,
+
+	`INSERT INTO thoughts (id, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at)
+	 VALUES (2, 'tended and rested', 'resting', 1, '2025-03-01T11:00:00Z', '2025-03-03T09:30:00.5Z', '2025-03-03T09:00:00Z', '2025-03-04T03:30:00.5Z')`,
+	`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note)
+	 VALUES (2, 'captured', '2025-03-01T11:00:00.0021Z', NULL, 'captured', NULL)`,
+	`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note)
+	 VALUES (2, 'state_change', '2025-03-03T09:00:00Z', 'captured', 'tended', 'a note')`,
+	`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note)
+	 VALUES (2, 'state_change', '2025-03-03T09:30:00.5Z', 'tended', 'resting', NULL)`,
+}
+
+func TestRebuildAcceptsBaselineHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peony.db")
+	seedBaselineDB(t, path, baselineGarden...)
+	st := openStoreAt(t, path)
+
+	before := make(map[int64][2]string)
+	for _, id := range []int64{1, 2} {
+		var created, updated string
+		if err := st.db.QueryRow(`SELECT created_at, updated_at FROM thoughts WHERE id = ?`, id).Scan(&created, &updated); err != nil {
+			t.Fatalf("read #%d: %v", id, err)
+		}
+		before[id] = [2]string{created, updated}
+	}
+
+	reports, err := st.RebuildSnapshots(0, true)
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	for _, r := range reports {
+		t.Errorf("#%d reported: no history %v, discrepancies %+v", r.ThoughtID, r.NoHistory, r.Discrepancies)
+	}
+
+	for id, want := range before {
+		var created, updated string
+		if err := st.db.QueryRow(`SELECT created_at, updated_at FROM thoughts WHERE id = ?`, id).Scan(&created, &updated); err != nil {
+			t.Fatalf("read #%d: %v", id, err)
+		}
+		if created != want[0] || updated != want[1] {
+			t.Errorf("#%d timestamps rewritten: %s, %s; want %s, %s", id, created, updated, want[0], want[1])
+		}
+	}
+}
+
+func TestRebuildStillReportsRealDrift(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "peony.db")
+	seedBaselineDB(t, path, baselineGarden...)
+	st := openStoreAt(t, path)
+
+	if _, err := st.db.Exec(`UPDATE thoughts SET current_state = 'captured', tend_counter = 0 WHERE id = 2`); err != nil {
+		t.Fatalf("corrupt snapshot: %v", err)
+	}
+	reports, err := st.RebuildSnapshots(0, false)
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if len(reports) != 1 || reports[0].ThoughtID != 2 {
+		t.Fatalf("reports = %+v, want only #2", reports)
+	}
+	fields := make(map[string]bool)
+	for _, d := range reports[0].Discrepancies {
+		fields[d.Field] = true
+	}
+	if !fields["current_state"] || !fields["tend_counter"] || fields["created_at"] || fields["updated_at"] {
+		t.Errorf("discrepancies = %+v, want state and tend count only", reports[0].Discrepancies)
+	}
+}
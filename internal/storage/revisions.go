@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// insertRevisionTx stores content as revision number of a thought, linked to the event that produced it.
+func insertRevisionTx(tx *sql.Tx, thoughtID, eventID int64, number int, content string, at string) error {
+	_, err := tx.Exec(
+		`INSERT INTO revisions (thought_id, event_id, number, content, created_at)
+		 VALUES (?, ?, ?, ?, ?)`,
+		thoughtID,
+		eventID,
+		number,
+		content,
+		at,
+	)
+	if err != nil {
+		return fmt.Errorf("insert revision: %w", err)
+	}
+	return nil
+}
+
+// ListRevisions returns every saved version of a thought's content, oldest first.
+func (s *Store) ListRevisions(id int64) ([]core.Revision, error) {
+	if s == nil {
+		return nil, fmt.Errorf("list revisions: store is nil")
+	}
+	if s.db == nil {
+		return nil, fmt.Errorf("list revisions: db is nil")
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("list revisions: invalid thought ID")
+	}
+
+	revisions, err := s.revisionsFor(id)
+	if err != nil {
+		return nil, fmt.Errorf("list revisions: %w", err)
+	}
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("list revisions: not found")
+	}
+	return revisions, nil
+}
+
+// revisionsFor returns a thought's revisions oldest first; a thought without any yields an empty slice.
+func (s *Store) revisionsFor(id int64) ([]core.Revision, error) {
+	rows, err := s.db.Query(
+		`SELECT id, thought_id, event_id, number, content, created_at
+		 FROM revisions
+		 WHERE thought_id = ?
+		 ORDER BY number ASC`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := make([]core.Revision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+	return revisions, nil
+}
+
+// GetRevision returns one saved version of a thought's content by its revision number.
+func (s *Store) GetRevision(id int64, number int) (core.Revision, error) {
+	if s == nil {
+		return core.Revision{}, fmt.Errorf("get revision: store is nil")
+	}
+	if s.db == nil {
+		return core.Revision{}, fmt.Errorf("get revision: db is nil")
+	}
+	if id <= 0 {
+		return core.Revision{}, fmt.Errorf("get revision: invalid thought ID")
+	}
+
+	row := s.db.QueryRow(
+		`SELECT id, thought_id, event_id, number, content, created_at
+		 FROM revisions
+		 WHERE thought_id = ? AND number = ?`,
+		id,
+		number,
+	)
+	revision, err := scanRevision(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.Revision{}, fmt.Errorf("get revision: #%d has no revision %d", id, number)
+		}
+		return core.Revision{}, fmt.Errorf("get revision: %w", err)
+	}
+	return revision, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRevision(row rowScanner) (core.Revision, error) {
+	var revision core.Revision
+	var eventID sql.NullInt64
+	var createdAtStr string
+	err := row.Scan(&revision.ID, &revision.ThoughtID, &eventID, &revision.Number, &revision.Content, &createdAtStr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return core.Revision{}, err
+		}
+		return core.Revision{}, fmt.Errorf("scan: %w", err)
+	}
+	if eventID.Valid {
+		revision.EventID = &eventID.Int64
+	}
+
+	revision.CreatedAt, err = parseTime(createdAtStr)
+	if err != nil {
+		return core.Revision{}, fmt.Errorf("parse created_at: %w", err)
+	}
+	return revision, nil
+}
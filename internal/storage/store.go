@@ -26,33 +26,121 @@ func New(db *sql.DB) (*Store, error) {
 	return &Store{db: db}, nil
 }
 
-// CreateThought inserts a new thought in captured state and returns its ID.
-func (s *Store) CreateThought(content string) (int64, error) {
+// CreateThought inserts a new thought in captured state together with its captured event,
+// and returns its display ID and permanent UID. settle decides when it first becomes eligible;
+// a non-zero feeling is stored and recorded as a feeling event.
+func (s *Store) CreateThought(content string, settle core.RestPeriod, feeling core.Feeling) (int64, string, error) {
 	if s == nil {
-		return -1, fmt.Errorf("create thought: store is nil")
+		return -1, "", fmt.Errorf("create thought: store is nil")
 	}
 	if s.db == nil {
-		return -1, fmt.Errorf("create thought: db is nil")
+		return -1, "", fmt.Errorf("create thought: db is nil")
 	}
 	if content == "" {
-		return -1, fmt.Errorf("create thought: content is empty")
+		return -1, "", fmt.Errorf("create thought: content is empty")
 	}
+	if settle.Until.IsZero() {
+		return -1, "", fmt.Errorf("create thought: settle period is zero")
+	}
+	uid, err := newThoughtUID()
+	if err != nil {
+		return -1, "", fmt.Errorf("create thought: %w", err)
+	}
+
+	tx, err := s.beginTx()
+	if err != nil {
+		return -1, "", fmt.Errorf("create thought: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
 	nowTime := time.Now().UTC()
-	now := nowTime.Format(time.RFC3339Nano)
-	eligibilityAt := nowTime.Add(core.SettleDuration).Format(time.RFC3339Nano)
+	now := formatTime(nowTime)
+	eligibilityAt := formatTime(settle.Until)
 	state := core.StateCaptured
-	sqlString := `INSERT INTO thoughts (content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy)
-	             VALUES (?, ?, 0, ?, ?, NULL, ?, NULL, NULL)`
-	var err error
+	sqlString := `INSERT INTO thoughts (uid, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy)
+	             VALUES (?, ?, ?, 0, ?, ?, NULL, ?, ?, ?)`
 	var result sql.Result
-	result, err = s.db.Exec(sqlString, content, string(state), now, now, eligibilityAt)
+	result, err = tx.Exec(sqlString, uid, content, string(state), now, now, eligibilityAt, nullableInt(feeling.Valence), nullableInt(feeling.Energy))
 	if err != nil {
-		return -1, fmt.Errorf("create thought: insert: %w", err)
+		return -1, "", fmt.Errorf("create thought: insert: %w", err)
 	}
 	var id int64
 	id, err = result.LastInsertId()
 	if err != nil {
-		return -1, fmt.Errorf("create thought: last insert id: %w", err)
+		return -1, "", fmt.Errorf("create thought: last insert id: %w", err)
+	}
+
+	var detailValue any
+	if settle.Reason != "" {
+		detailValue = settle.Reason
+	}
+	result, err = tx.Exec(
+		`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note, eligibility_at, detail)
+		 VALUES (?, ?, ?, NULL, ?, NULL, ?, ?)`,
+		id,
+		core.EventCaptured,
+		now,
+		string(state),
+		eligibilityAt,
+		detailValue,
+	)
+	if err != nil {
+		return -1, "", fmt.Errorf("create thought: insert event: %w", err)
+	}
+	eventID, err := result.LastInsertId()
+	if err != nil {
+		return -1, "", fmt.Errorf("create thought: last event id: %w", err)
+	}
+
+	err = insertRevisionTx(tx, id, eventID, 1, content, now)
+	if err != nil {
+		return -1, "", fmt.Errorf("create thought: %w", err)
+	}
+
+	if !feeling.IsZero() {
+		err = insertFeelingEventTx(tx, id, now, feeling.DescribeChange(core.Feeling{}))
+		if err != nil {
+			return -1, "", fmt.Errorf("create thought: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, "", fmt.Errorf("create thought: commit: %w", err)
+	}
+	return id, uid, nil
+}
+
+// ResolveThoughtRef maps a user-supplied reference to a thought's display ID.
+// All-digit references are display IDs; anything else is matched against the permanent UID.
+func (s *Store) ResolveThoughtRef(ref string) (int64, error) {
+	if s == nil {
+		return -1, fmt.Errorf("resolve thought: store is nil")
+	}
+	if s.db == nil {
+		return -1, fmt.Errorf("resolve thought: db is nil")
+	}
+
+	ref = strings.TrimPrefix(strings.TrimSpace(ref), "#")
+	if ref == "" {
+		return -1, fmt.Errorf("resolve thought: empty reference")
+	}
+
+	if id, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		if id <= 0 {
+			return -1, fmt.Errorf("resolve thought: invalid thought ID")
+		}
+		return id, nil
+	}
+
+	var id int64
+	err := s.db.QueryRow(`SELECT id FROM thoughts WHERE uid = ?`, strings.ToLower(ref)).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, fmt.Errorf("resolve thought: no thought with uid %q", ref)
+		}
+		return -1, fmt.Errorf("resolve thought: query: %w", err)
 	}
 	return id, nil
 }
@@ -71,7 +159,7 @@ func (s *Store) AppendEvent(thoughtID int64, kind string, previousState, nextSta
 	if kind == "" {
 		return fmt.Errorf("append event: kind is empty")
 	}
-	now := time.Now().UTC().Format(time.RFC3339Nano)
+	now := formatTime(time.Now())
 
 	var previousStateValue any
 	if previousState != nil {
@@ -115,7 +203,7 @@ func (s *Store) GetThought(id int64) (core.Thought, []core.Event, error) {
 		return core.Thought{}, nil, fmt.Errorf("get thought: invalid thought ID")
 	}
 
-	sqlThought := `SELECT id, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy FROM thoughts WHERE id = ?`
+	sqlThought := `SELECT id, uid, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy FROM thoughts WHERE id = ?`
 
 	var thought core.Thought
 	var createdAtStr, updatedAtStr string
@@ -128,7 +216,7 @@ func (s *Store) GetThought(id int64) (core.Thought, []core.Event, error) {
 
 	var err error
 	row := s.db.QueryRow(sqlThought, id)
-	err = row.Scan(&thought.ID, &thought.Content, &stateStr, &tendCounter, &createdAtStr, &updatedAtStr, &lastTendedAtStr, &eligibilityAtStr, &valence, &energy)
+	err = row.Scan(&thought.ID, &thought.UID, &thought.Content, &stateStr, &tendCounter, &createdAtStr, &updatedAtStr, &lastTendedAtStr, &eligibilityAtStr, &valence, &energy)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return core.Thought{}, nil, fmt.Errorf("get thought: not found")
@@ -139,23 +227,23 @@ func (s *Store) GetThought(id int64) (core.Thought, []core.Event, error) {
 	thought.CurrentState = core.State(stateStr)
 	thought.TendCounter = tendCounter
 
-	thought.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAtStr)
+	thought.CreatedAt, err = parseTime(createdAtStr)
 	if err != nil {
 		return core.Thought{}, nil, fmt.Errorf("get thought: parse created_at: %w", err)
 	}
-	thought.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAtStr)
+	thought.UpdatedAt, err = parseTime(updatedAtStr)
 	if err != nil {
 		return core.Thought{}, nil, fmt.Errorf("get thought: parse updated_at: %w", err)
 	}
 
-	thought.EligibilityAt, err = time.Parse(time.RFC3339Nano, eligibilityAtStr)
+	thought.EligibilityAt, err = parseTime(eligibilityAtStr)
 	if err != nil {
 		return core.Thought{}, nil, fmt.Errorf("get thought: parse eligibility_at: %w", err)
 	}
 
 	if lastTendedAtStr.Valid {
 		var t time.Time
-		t, err = time.Parse(time.RFC3339Nano, lastTendedAtStr.String)
+		t, err = parseTime(lastTendedAtStr.String)
 		if err != nil {
 			return core.Thought{}, nil, fmt.Errorf("get thought: parse last_tended_at: %w", err)
 		}
@@ -172,7 +260,12 @@ func (s *Store) GetThought(id int64) (core.Thought, []core.Event, error) {
 		thought.Energy = &e
 	}
 
-	sqlEvents := `SELECT id, thought_id, kind, at, previous_state, next_state, note FROM events WHERE thought_id = ? ORDER BY at ASC, id ASC`
+	thought.Tags, err = readTags(s.db, id)
+	if err != nil {
+		return core.Thought{}, nil, fmt.Errorf("get thought: %w", err)
+	}
+
+	sqlEvents := `SELECT id, thought_id, kind, at, previous_state, next_state, note, eligibility_at, detail FROM events WHERE thought_id = ? ORDER BY at ASC, id ASC`
 	var rows *sql.Rows
 	rows, err = s.db.Query(sqlEvents, id)
 	if err != nil {
@@ -187,13 +280,15 @@ func (s *Store) GetThought(id int64) (core.Thought, []core.Event, error) {
 		var previousStateStr sql.NullString
 		var nextStateStr sql.NullString
 		var noteStr sql.NullString
+		var eligibilityAtStr sql.NullString
+		var detailStr sql.NullString
 
-		err = rows.Scan(&event.ID, &event.ThoughtID, &event.Kind, &atStr, &previousStateStr, &nextStateStr, &noteStr)
+		err = rows.Scan(&event.ID, &event.ThoughtID, &event.Kind, &atStr, &previousStateStr, &nextStateStr, &noteStr, &eligibilityAtStr, &detailStr)
 		if err != nil {
 			return core.Thought{}, nil, fmt.Errorf("get thought: scan event: %w", err)
 		}
 
-		event.At, err = time.Parse(time.RFC3339Nano, atStr)
+		event.At, err = parseTime(atStr)
 		if err != nil {
 			return core.Thought{}, nil, fmt.Errorf("get thought: parse event at: %w", err)
 		}
@@ -211,6 +306,20 @@ func (s *Store) GetThought(id int64) (core.Thought, []core.Event, error) {
 			event.Note = &n
 		}
 
+		if eligibilityAtStr.Valid {
+			var t time.Time
+			t, err = parseTime(eligibilityAtStr.String)
+			if err != nil {
+				return core.Thought{}, nil, fmt.Errorf("get thought: parse event eligibility_at: %w", err)
+			}
+			event.EligibilityAt = &t
+		}
+
+		if detailStr.Valid {
+			d := detailStr.String
+			event.Detail = &d
+		}
+
 		events = append(events, event)
 	}
 
@@ -234,9 +343,21 @@ func (s *Store) GetTendThought(id int64) (core.Thought, []core.Event, error) {
 		return core.Thought{}, nil, fmt.Errorf("get thought: invalid thought ID")
 	}
 
-	nowStr := time.Now().UTC().Format(time.RFC3339Nano)
+	nowTime := time.Now()
+	if !core.Windows.Open(nowTime) {
+		return core.Thought{}, nil, fmt.Errorf("get thought: outside surfacing hours (%s)", core.Windows.Describe())
+	}
+	nowStr := formatTime(nowTime)
+
+	selected, limited, err := s.todaysSelection(nowTime)
+	if err != nil {
+		return core.Thought{}, nil, fmt.Errorf("get thought: %w", err)
+	}
+	if _, ok := selected[id]; limited && !ok {
+		return core.Thought{}, nil, fmt.Errorf("get thought: not among today's %d thoughts; it will wait quietly", core.DailyBudget)
+	}
 
-	sqlThought := `SELECT id, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy
+	sqlThought := `SELECT id, uid, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy
 	               FROM thoughts
 				   WHERE id = ? AND current_state IN (?, ?) AND eligibility_at <= ?
 				  `
@@ -250,9 +371,8 @@ func (s *Store) GetTendThought(id int64) (core.Thought, []core.Event, error) {
 	var tendCounter int
 	var eligibilityAtStr string
 
-	var err error
 	row := s.db.QueryRow(sqlThought, id, string(core.StateCaptured), string(core.StateResting), nowStr)
-	err = row.Scan(&thought.ID, &thought.Content, &stateStr, &tendCounter, &createdAtStr, &updatedAtStr, &lastTendedAtStr, &eligibilityAtStr, &valence, &energy)
+	err = row.Scan(&thought.ID, &thought.UID, &thought.Content, &stateStr, &tendCounter, &createdAtStr, &updatedAtStr, &lastTendedAtStr, &eligibilityAtStr, &valence, &energy)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return core.Thought{}, nil, fmt.Errorf("get thought: not found")
@@ -263,24 +383,24 @@ func (s *Store) GetTendThought(id int64) (core.Thought, []core.Event, error) {
 	thought.CurrentState = core.State(stateStr)
 	thought.TendCounter = tendCounter
 
-	thought.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAtStr)
+	thought.CreatedAt, err = parseTime(createdAtStr)
 	if err != nil {
 		return core.Thought{}, nil, fmt.Errorf("get thought: parse created_at: %w", err)
 	}
 
-	thought.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAtStr)
+	thought.UpdatedAt, err = parseTime(updatedAtStr)
 	if err != nil {
 		return core.Thought{}, nil, fmt.Errorf("get thought: parse updated_at: %w", err)
 	}
 
-	thought.EligibilityAt, err = time.Parse(time.RFC3339Nano, eligibilityAtStr)
+	thought.EligibilityAt, err = parseTime(eligibilityAtStr)
 	if err != nil {
 		return core.Thought{}, nil, fmt.Errorf("get thought: parse eligibility_at: %w", err)
 	}
 
 	if lastTendedAtStr.Valid {
 		var t time.Time
-		t, err = time.Parse(time.RFC3339Nano, lastTendedAtStr.String)
+		t, err = parseTime(lastTendedAtStr.String)
 		if err != nil {
 			return core.Thought{}, nil, fmt.Errorf("get thought: parse last_tended_at: %w", err)
 		}
@@ -297,7 +417,12 @@ func (s *Store) GetTendThought(id int64) (core.Thought, []core.Event, error) {
 		thought.Energy = &e
 	}
 
-	sqlEvents := `SELECT id, thought_id, kind, at, previous_state, next_state, note
+	thought.Tags, err = readTags(s.db, id)
+	if err != nil {
+		return core.Thought{}, nil, fmt.Errorf("get thought: %w", err)
+	}
+
+	sqlEvents := `SELECT id, thought_id, kind, at, previous_state, next_state, note, eligibility_at, detail
 	              FROM events
 	              WHERE thought_id = ?
 	              ORDER BY at ASC, id ASC
@@ -316,13 +441,15 @@ func (s *Store) GetTendThought(id int64) (core.Thought, []core.Event, error) {
 		var previousStateStr sql.NullString
 		var nextStateStr sql.NullString
 		var noteStr sql.NullString
+		var eligibilityAtStr sql.NullString
+		var detailStr sql.NullString
 
-		err = rows.Scan(&event.ID, &event.ThoughtID, &event.Kind, &atStr, &previousStateStr, &nextStateStr, &noteStr)
+		err = rows.Scan(&event.ID, &event.ThoughtID, &event.Kind, &atStr, &previousStateStr, &nextStateStr, &noteStr, &eligibilityAtStr, &detailStr)
 		if err != nil {
 			return core.Thought{}, nil, fmt.Errorf("get thought: scan event: %w", err)
 		}
 
-		event.At, err = time.Parse(time.RFC3339Nano, atStr)
+		event.At, err = parseTime(atStr)
 		if err != nil {
 			return core.Thought{}, nil, fmt.Errorf("get thought: parse event at: %w", err)
 		}
@@ -342,6 +469,20 @@ func (s *Store) GetTendThought(id int64) (core.Thought, []core.Event, error) {
 			event.Note = &n
 		}
 
+		if eligibilityAtStr.Valid {
+			var t time.Time
+			t, err = parseTime(eligibilityAtStr.String)
+			if err != nil {
+				return core.Thought{}, nil, fmt.Errorf("get thought: parse event eligibility_at: %w", err)
+			}
+			event.EligibilityAt = &t
+		}
+
+		if detailStr.Valid {
+			d := detailStr.String
+			event.Detail = &d
+		}
+
 		events = append(events, event)
 	}
 
@@ -367,7 +508,7 @@ func (s *Store) ListThoughtsByPagination(limit, offset int) ([]core.Thought, err
 		return nil, fmt.Errorf("list thoughts: offset must be >= 0")
 	}
 
-	sqlList := `SELECT id, content, current_state, tend_counter, updated_at
+	sqlList := `SELECT id, uid, content, current_state, tend_counter, updated_at
                 FROM thoughts
                 ORDER BY updated_at ASC, id ASC
                 LIMIT ? OFFSET ?`
@@ -384,13 +525,13 @@ func (s *Store) ListThoughtsByPagination(limit, offset int) ([]core.Thought, err
 		var stateStr string
 		var updatedAtStr string
 
-		if err := rows.Scan(&thought.ID, &thought.Content, &stateStr, &thought.TendCounter, &updatedAtStr); err != nil {
+		if err := rows.Scan(&thought.ID, &thought.UID, &thought.Content, &stateStr, &thought.TendCounter, &updatedAtStr); err != nil {
 			return nil, fmt.Errorf("list thoughts: scan: %w", err)
 		}
 
 		thought.CurrentState = core.State(stateStr)
 
-		thought.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAtStr)
+		thought.UpdatedAt, err = parseTime(updatedAtStr)
 		if err != nil {
 			return nil, fmt.Errorf("list thoughts: parse updated_at: %w", err)
 		}
@@ -406,6 +547,7 @@ func (s *Store) ListThoughtsByPagination(limit, offset int) ([]core.Thought, err
 }
 
 // ListTendThoughtsByPagination returns a page of thoughts eligible for tending ordered by eligibility time and ID.
+// Outside the configured surfacing windows the page is empty.
 func (s *Store) ListTendThoughtsByPagination(limit, offset int) ([]core.Thought, error) {
 	if s == nil {
 		return nil, fmt.Errorf("list tend thoughts: store is nil")
@@ -420,16 +562,39 @@ func (s *Store) ListTendThoughtsByPagination(limit, offset int) ([]core.Thought,
 		return nil, fmt.Errorf("list tend thoughts: offset must be >= 0")
 	}
 
-	nowStr := time.Now().UTC().Format(time.RFC3339Nano)
+	nowTime := time.Now()
+	if !core.Windows.Open(nowTime) {
+		return []core.Thought{}, nil
+	}
+	nowStr := formatTime(nowTime)
+
+	selected, limited, err := s.todaysSelection(nowTime)
+	if err != nil {
+		return nil, fmt.Errorf("list tend thoughts: %w", err)
+	}
+	if limited && len(selected) == 0 {
+		return []core.Thought{}, nil
+	}
+
+	queryArgs := []any{string(core.StateCaptured), string(core.StateResting), nowStr}
+	selectionClause := ""
+	if limited {
+		selectionClause = "AND id IN (?" + strings.Repeat(", ?", len(selected)-1) + ")"
+		for selectedID := range selected {
+			queryArgs = append(queryArgs, selectedID)
+		}
+	}
+	queryArgs = append(queryArgs, limit, offset)
 
-	sqlList := `SELECT id, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy
+	sqlList := `SELECT id, uid, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy
 	            FROM thoughts
 	            WHERE current_state IN (?, ?)
 	              AND eligibility_at <= ?
+	              ` + selectionClause + `
 	            ORDER BY eligibility_at ASC, id ASC
 	            LIMIT ? OFFSET ?`
 
-	rows, err := s.db.Query(sqlList, string(core.StateCaptured), string(core.StateResting), nowStr, limit, offset)
+	rows, err := s.db.Query(sqlList, queryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("list tend thoughts: query: %w", err)
 	}
@@ -447,7 +612,7 @@ func (s *Store) ListTendThoughtsByPagination(limit, offset int) ([]core.Thought,
 		var valence sql.NullInt64
 		var energy sql.NullInt64
 
-		err = rows.Scan(&thought.ID, &thought.Content, &stateStr, &tendCounter, &createdAtStr, &updatedAtStr, &lastTendedAtStr, &eligibilityAtStr, &valence, &energy)
+		err = rows.Scan(&thought.ID, &thought.UID, &thought.Content, &stateStr, &tendCounter, &createdAtStr, &updatedAtStr, &lastTendedAtStr, &eligibilityAtStr, &valence, &energy)
 		if err != nil {
 			return nil, fmt.Errorf("list tend thoughts: scan: %w", err)
 		}
@@ -456,23 +621,23 @@ func (s *Store) ListTendThoughtsByPagination(limit, offset int) ([]core.Thought,
 		thought.TendCounter = tendCounter
 
 		var err error
-		thought.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAtStr)
+		thought.CreatedAt, err = parseTime(createdAtStr)
 		if err != nil {
 			return nil, fmt.Errorf("list tend thoughts: parse created_at: %w", err)
 		}
 
-		thought.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAtStr)
+		thought.UpdatedAt, err = parseTime(updatedAtStr)
 		if err != nil {
 			return nil, fmt.Errorf("list tend thoughts: parse updated_at: %w", err)
 		}
 
-		thought.EligibilityAt, err = time.Parse(time.RFC3339Nano, eligibilityAtStr)
+		thought.EligibilityAt, err = parseTime(eligibilityAtStr)
 		if err != nil {
 			return nil, fmt.Errorf("list tend thoughts: parse eligibility_at: %w", err)
 		}
 
 		if lastTendedAtStr.Valid {
-			t, err := time.Parse(time.RFC3339Nano, lastTendedAtStr.String)
+			t, err := parseTime(lastTendedAtStr.String)
 			if err != nil {
 				return nil, fmt.Errorf("list tend thoughts: parse last_tended_at: %w", err)
 			}
@@ -512,7 +677,7 @@ func (s *Store) FilterViewByPagination(limit, offset int, filter string) ([]core
 		return nil, fmt.Errorf("list view thoughts: offset must be >= 0")
 	}
 
-	sqlList := `SELECT id, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy
+	sqlList := `SELECT id, uid, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy
 	            FROM thoughts
 	            WHERE current_state IN (?)
 	            ORDER BY id ASC
@@ -536,7 +701,7 @@ func (s *Store) FilterViewByPagination(limit, offset int, filter string) ([]core
 		var valence sql.NullInt64
 		var energy sql.NullInt64
 
-		err = rows.Scan(&thought.ID, &thought.Content, &stateStr, &tendCounter, &createdAtStr, &updatedAtStr, &lastTendedAtStr, &eligibilityAtStr, &valence, &energy)
+		err = rows.Scan(&thought.ID, &thought.UID, &thought.Content, &stateStr, &tendCounter, &createdAtStr, &updatedAtStr, &lastTendedAtStr, &eligibilityAtStr, &valence, &energy)
 		if err != nil {
 			return nil, fmt.Errorf("list view thoughts: scan: %w", err)
 		}
@@ -545,23 +710,23 @@ func (s *Store) FilterViewByPagination(limit, offset int, filter string) ([]core
 		thought.TendCounter = tendCounter
 
 		var err error
-		thought.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAtStr)
+		thought.CreatedAt, err = parseTime(createdAtStr)
 		if err != nil {
 			return nil, fmt.Errorf("list view thoughts: parse created_at: %w", err)
 		}
 
-		thought.UpdatedAt, err = time.Parse(time.RFC3339Nano, updatedAtStr)
+		thought.UpdatedAt, err = parseTime(updatedAtStr)
 		if err != nil {
 			return nil, fmt.Errorf("list view thoughts: parse updated_at: %w", err)
 		}
 
-		thought.EligibilityAt, err = time.Parse(time.RFC3339Nano, eligibilityAtStr)
+		thought.EligibilityAt, err = parseTime(eligibilityAtStr)
 		if err != nil {
 			return nil, fmt.Errorf("list view thoughts: parse eligibility_at: %w", err)
 		}
 
 		if lastTendedAtStr.Valid {
-			t, err := time.Parse(time.RFC3339Nano, lastTendedAtStr.String)
+			t, err := parseTime(lastTendedAtStr.String)
 			if err != nil {
 				return nil, fmt.Errorf("list view thoughts: parse last_tended_at: %w", err)
 			}
@@ -587,8 +752,10 @@ func (s *Store) FilterViewByPagination(limit, offset int, filter string) ([]core
 	return thoughts, nil
 }
 
-// UpdateThoughtContent updates a thought's content and refreshed updated_at.
-func (s *Store) UpdateThoughtContent(id int64, content string) error {
+// UpdateThoughtContent updates a thought's content and refreshed updated_at. expected is the
+// updated_at read before editing; if the thought was saved since, a *ConflictError is returned
+// and nothing changes. A zero expected skips the check.
+func (s *Store) UpdateThoughtContent(id int64, content string, expected time.Time) error {
 	if s == nil {
 		return fmt.Errorf("update thought content: store is nil")
 	}
@@ -602,100 +769,282 @@ func (s *Store) UpdateThoughtContent(id int64, content string) error {
 		return fmt.Errorf("update thought content: content is empty")
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339Nano)
-	result, err := s.db.Exec(
+	tx, err := s.beginTx()
+	if err != nil {
+		return fmt.Errorf("update thought content: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := checkUnchangedTx(tx, id, expected); err != nil {
+		return fmt.Errorf("update thought content: %w", err)
+	}
+
+	now := formatTime(time.Now())
+	if err := updateContentTx(tx, id, content, now); err != nil {
+		return fmt.Errorf("update thought content: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("update thought content: commit: %w", err)
+	}
+	return nil
+}
+
+// updateContentTx replaces a thought's content inside tx, keeping the new content as the next
+// revision linked to a revised event. Unchanged content is left alone.
+func updateContentTx(tx *sql.Tx, id int64, content string, at string) error {
+	var current string
+	if err := tx.QueryRow(`SELECT content FROM thoughts WHERE id = ?`, id).Scan(&current); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("not found (id=%d)", id)
+		}
+		return fmt.Errorf("read content: %w", err)
+	}
+	if current == content {
+		return nil
+	}
+
+	_, err := tx.Exec(
 		`UPDATE thoughts SET content = ?, updated_at = ? WHERE id = ?`,
 		content,
-		now,
+		at,
 		id,
 	)
 	if err != nil {
-		return fmt.Errorf("update thought content: update: %w", err)
+		return fmt.Errorf("update: %w", err)
 	}
 
-	rows, err := result.RowsAffected()
+	var number int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(number), 0) + 1 FROM revisions WHERE thought_id = ?`, id).Scan(&number); err != nil {
+		return fmt.Errorf("next revision: %w", err)
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note, eligibility_at, detail)
+		 VALUES (?, ?, ?, NULL, NULL, NULL, NULL, ?)`,
+		id,
+		core.EventRevised,
+		at,
+		fmt.Sprintf("revision %d", number),
+	)
 	if err != nil {
-		return fmt.Errorf("update thought content: rows affected: %w", err)
+		return fmt.Errorf("insert revised event: %w", err)
 	}
-	if rows == 0 {
-		return fmt.Errorf("update thought content: no rows updated (id=%d)", id)
+	eventID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("last event id: %w", err)
 	}
 
-	return nil
+	return insertRevisionTx(tx, id, eventID, number, content, at)
 }
 
-// MarkThoughtTended transitions a thought to tended, increments tend_counter, and appends a state-change event.
-func (s *Store) MarkThoughtTended(id int64, note *string) error {
+// transitionParams carries the optional snapshot changes that accompany a lifecycle move.
+// A nil rest with spaced set asks for the rest period chosen by core.ResurfacePolicy.
+type transitionParams struct {
+	note   *string
+	rest   *core.RestPeriod
+	spaced bool
+}
+
+// transitionTx moves a thought to next inside tx, checking the move against core's transition table
+// and appending the matching event. It returns the state the thought was in before the move.
+func transitionTx(tx *sql.Tx, id int64, next core.State, at time.Time, params transitionParams) (core.State, error) {
+	var prevStateStr string
+	var tendCounter int
+	row := tx.QueryRow(`SELECT current_state, tend_counter FROM thoughts WHERE id = ?`, id)
+	if err := row.Scan(&prevStateStr, &tendCounter); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("not found")
+		}
+		return "", fmt.Errorf("read current_state: %w", err)
+	}
+
+	prev := core.State(prevStateStr)
+	transition, err := core.CheckTransition(prev, next)
+	if err != nil {
+		return prev, err
+	}
+
+	if params.rest == nil && params.spaced {
+		spacedRest := core.SpacedRest(at.UTC(), tendCounter)
+		params.rest = &spacedRest
+	}
+
+	now := formatTime(at)
+	sets := []string{"current_state = ?", "updated_at = ?"}
+	args := []any{string(next), now}
+	if next == core.StateTended {
+		sets = append(sets, "tend_counter = tend_counter + 1", "last_tended_at = ?")
+		args = append(args, now)
+	}
+	var eligibilityAtValue any
+	var detailValue any
+	if params.rest != nil {
+		eligibilityAt := formatTime(params.rest.Until)
+		sets = append(sets, "eligibility_at = ?")
+		args = append(args, eligibilityAt)
+		eligibilityAtValue = eligibilityAt
+		if params.rest.Reason != "" {
+			detailValue = params.rest.Reason
+		}
+	}
+	args = append(args, id)
+
+	_, err = tx.Exec(`UPDATE thoughts SET `+strings.Join(sets, ", ")+` WHERE id = ?`, args...)
+	if err != nil {
+		return prev, fmt.Errorf("update thoughts: %w", err)
+	}
+
+	var noteValue any
+	if params.note != nil && strings.TrimSpace(*params.note) != "" {
+		noteValue = *params.note
+	} else {
+		noteValue = nil
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note, eligibility_at, detail)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		id,
+		transition.Kind,
+		now,
+		string(prev),
+		string(next),
+		noteValue,
+		eligibilityAtValue,
+		detailValue,
+	)
+	if err != nil {
+		return prev, fmt.Errorf("insert event: %w", err)
+	}
+
+	return prev, nil
+}
+
+// UpdateThoughtFeeling sets a thought's valence and energy (nil clears a field) and appends a feeling event
+// describing the change. It is a no-op when nothing changed. expected guards against overwriting a
+// newer save, as in UpdateThoughtContent.
+func (s *Store) UpdateThoughtFeeling(id int64, feeling core.Feeling, expected time.Time) error {
 	if s == nil {
-		return fmt.Errorf("mark thought tended: store is nil")
+		return fmt.Errorf("update thought feeling: store is nil")
 	}
 	if s.db == nil {
-		return fmt.Errorf("mark thought tended: db is nil")
+		return fmt.Errorf("update thought feeling: db is nil")
 	}
 	if id <= 0 {
-		return fmt.Errorf("mark thought tended: invalid thought ID")
+		return fmt.Errorf("update thought feeling: invalid thought ID")
 	}
 
-	tx, err := s.db.Begin()
+	tx, err := s.beginTx()
 	if err != nil {
-		return fmt.Errorf("mark thought tended: begin tx: %w", err)
+		return fmt.Errorf("update thought feeling: begin tx: %w", err)
 	}
 	defer func() {
 		_ = tx.Rollback()
 	}()
 
-	var prevStateStr string
-	row := tx.QueryRow(`SELECT current_state FROM thoughts WHERE id = ?`, id)
-	if err := row.Scan(&prevStateStr); err != nil {
+	if err := checkUnchangedTx(tx, id, expected); err != nil {
+		return fmt.Errorf("update thought feeling: %w", err)
+	}
+
+	now := formatTime(time.Now())
+	if err := updateFeelingTx(tx, id, feeling, now); err != nil {
+		return fmt.Errorf("update thought feeling: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("update thought feeling: commit: %w", err)
+	}
+	return nil
+}
+
+// updateFeelingTx sets a thought's valence and energy inside tx, appending a feeling event when they change.
+func updateFeelingTx(tx *sql.Tx, id int64, feeling core.Feeling, at string) error {
+	var valence, energy sql.NullInt64
+	row := tx.QueryRow(`SELECT valence, energy FROM thoughts WHERE id = ?`, id)
+	if err := row.Scan(&valence, &energy); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("mark thought tended: not found")
+			return fmt.Errorf("not found")
 		}
-		return fmt.Errorf("mark thought tended: read current_state: %w", err)
+		return fmt.Errorf("read feeling: %w", err)
 	}
 
-	prev := core.State(prevStateStr)
-	if prev == core.StateEvolved || prev == core.StateReleased || prev == core.StateArchived {
-		return fmt.Errorf("mark thought tended: thought is in terminal state (%s)", prev)
+	var prev core.Feeling
+	if valence.Valid {
+		v := int(valence.Int64)
+		prev.Valence = &v
+	}
+	if energy.Valid {
+		e := int(energy.Int64)
+		prev.Energy = &e
+	}
+	if prev.Equal(feeling) {
+		return nil
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339Nano)
-	next := core.StateTended
-
-	_, err = tx.Exec(
-		`UPDATE thoughts
-		 SET current_state = ?,
-		     tend_counter = tend_counter + 1,
-		     last_tended_at = ?,
-		     updated_at = ?
-		 WHERE id = ?`,
-		string(next),
-		now,
-		now,
+	_, err := tx.Exec(
+		`UPDATE thoughts SET valence = ?, energy = ?, updated_at = ? WHERE id = ?`,
+		nullableInt(feeling.Valence),
+		nullableInt(feeling.Energy),
+		at,
 		id,
 	)
 	if err != nil {
-		return fmt.Errorf("mark thought tended: update thoughts: %w", err)
+		return fmt.Errorf("update: %w", err)
 	}
 
-	var noteValue any
-	if note != nil && strings.TrimSpace(*note) != "" {
-		noteValue = *note
-	} else {
-		noteValue = nil
-	}
+	return insertFeelingEventTx(tx, id, at, feeling.DescribeChange(prev))
+}
 
-	_, err = tx.Exec(
-		`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note)
-		 VALUES (?, ?, ?, ?, ?, ?)`,
+// insertFeelingEventTx appends a feeling event carrying a description of the change.
+func insertFeelingEventTx(tx *sql.Tx, id int64, at string, detail string) error {
+	_, err := tx.Exec(
+		`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note, eligibility_at, detail)
+		 VALUES (?, ?, ?, NULL, NULL, NULL, NULL, ?)`,
 		id,
-		"state_change",
-		now,
-		string(prev),
-		string(next),
-		noteValue,
+		core.EventFeeling,
+		at,
+		detail,
 	)
 	if err != nil {
-		return fmt.Errorf("mark thought tended: insert event: %w", err)
+		return fmt.Errorf("insert feeling event: %w", err)
+	}
+	return nil
+}
+
+// nullableInt converts an optional int into a value suitable for a nullable column.
+func nullableInt(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+// MarkThoughtTended transitions a thought to tended, increments tend_counter, and appends a state-change event.
+func (s *Store) MarkThoughtTended(id int64, note *string) error {
+	if s == nil {
+		return fmt.Errorf("mark thought tended: store is nil")
+	}
+	if s.db == nil {
+		return fmt.Errorf("mark thought tended: db is nil")
+	}
+	if id <= 0 {
+		return fmt.Errorf("mark thought tended: invalid thought ID")
+	}
+
+	tx, err := s.beginTx()
+	if err != nil {
+		return fmt.Errorf("mark thought tended: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = transitionTx(tx, id, core.StateTended, time.Now(), transitionParams{note: note})
+	if err != nil {
+		return fmt.Errorf("mark thought tended: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -705,7 +1054,8 @@ func (s *Store) MarkThoughtTended(id int64, note *string) error {
 }
 
 // TransitionPostTendResolutionStrict transitions a tended thought into resting or a terminal state and appends exactly one event.
-func (s *Store) TransitionPostTendResolutionStrict(id int64, next core.State, note *string) error {
+// rest is only used when next is resting; nil lets core.ResurfacePolicy choose from the thought's tend count.
+func (s *Store) TransitionPostTendResolutionStrict(id int64, next core.State, note *string, rest *core.RestPeriod) error {
 	if s == nil {
 		return fmt.Errorf("post-tend transition: store is nil")
 	}
@@ -716,11 +1066,7 @@ func (s *Store) TransitionPostTendResolutionStrict(id int64, next core.State, no
 		return fmt.Errorf("post-tend transition: invalid thought ID")
 	}
 
-	if next != core.StateResting && next != core.StateEvolved && next != core.StateReleased && next != core.StateArchived {
-		return fmt.Errorf("post-tend transition: invalid next state %q", next)
-	}
-
-	tx, err := s.db.Begin()
+	tx, err := s.beginTx()
 	if err != nil {
 		return fmt.Errorf("post-tend transition: begin tx: %w", err)
 	}
@@ -728,82 +1074,147 @@ func (s *Store) TransitionPostTendResolutionStrict(id int64, next core.State, no
 		_ = tx.Rollback()
 	}()
 
-	var prevStateStr string
-	row := tx.QueryRow(`SELECT current_state FROM thoughts WHERE id = ?`, id)
-	if err := row.Scan(&prevStateStr); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("post-tend transition: not found")
-		}
-		return fmt.Errorf("post-tend transition: read current_state: %w", err)
+	nowTime := time.Now().UTC()
+	params := transitionParams{note: note}
+	if next == core.StateResting {
+		params.rest = rest
+		params.spaced = true
 	}
 
-	prev := core.State(prevStateStr)
+	prev, err := transitionTx(tx, id, next, nowTime, params)
+	if err != nil {
+		return fmt.Errorf("post-tend transition: %w", err)
+	}
 	if prev != core.StateTended {
-		return fmt.Errorf("post-tend transition: thought is not in tended state (currently %s)", prev)
+		return fmt.Errorf("post-tend transition: %w", &core.TransitionError{From: prev, To: next})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("post-tend transition: commit: %w", err)
+	}
+
+	return nil
+}
+
+// TendDecisions stages everything decided while tending a thought so it can be saved at once.
+type TendDecisions struct {
+	Content string
+	Feeling core.Feeling
+	// Tags replaces the thought's tags when non-nil; nil leaves them as they are.
+	Tags []string
+	// Mark records the tend; Note is only kept when Mark is set.
+	Mark bool
+	Note *string
+	// Next resolves a marked thought. Rest is only used when Next is resting; nil lets
+	// core.ResurfacePolicy choose from the thought's tend count.
+	Next core.State
+	Rest *core.RestPeriod
+	// Expected is the thought's updated_at when the tend began. If it was saved since,
+	// CommitTend returns a *ConflictError instead of overwriting it. Zero skips the check.
+	Expected time.Time
+}
+
+// CommitTend saves the outcome of a tend in a single transaction: the edited content and feeling,
+// the tended event and the resolution that follows it. Either all of it is kept or none of it is.
+func (s *Store) CommitTend(id int64, decisions TendDecisions) error {
+	if s == nil {
+		return fmt.Errorf("commit tend: store is nil")
+	}
+	if s.db == nil {
+		return fmt.Errorf("commit tend: db is nil")
+	}
+	if id <= 0 {
+		return fmt.Errorf("commit tend: invalid thought ID")
+	}
+	if strings.TrimSpace(decisions.Content) == "" {
+		return fmt.Errorf("commit tend: content is empty")
+	}
+	if decisions.Mark && decisions.Next == "" {
+		return fmt.Errorf("commit tend: a tended thought needs a resolution")
+	}
+
+	tx, err := s.beginTx()
+	if err != nil {
+		return fmt.Errorf("commit tend: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := checkUnchangedTx(tx, id, decisions.Expected); err != nil {
+		return fmt.Errorf("commit tend: %w", err)
 	}
 
 	nowTime := time.Now().UTC()
-	now := nowTime.Format(time.RFC3339Nano)
+	now := formatTime(nowTime)
 
-	var noteValue any
-	if note != nil && strings.TrimSpace(*note) != "" {
-		noteValue = *note
-	} else {
-		noteValue = nil
+	if err := updateContentTx(tx, id, decisions.Content, now); err != nil {
+		return fmt.Errorf("commit tend: save content: %w", err)
+	}
+	if err := updateFeelingTx(tx, id, decisions.Feeling, now); err != nil {
+		return fmt.Errorf("commit tend: save feeling: %w", err)
+	}
+	if decisions.Tags != nil {
+		if err := setTagsTx(tx, id, decisions.Tags, now); err != nil {
+			return fmt.Errorf("commit tend: save tags: %w", err)
+		}
 	}
 
-	if next == core.StateResting {
-		eligibilityAt := nowTime.Add(core.SettleDuration).Format(time.RFC3339Nano)
-		_, err = tx.Exec(
-			`UPDATE thoughts
-			 SET current_state = ?,
-			     updated_at = ?,
-			     eligibility_at = ?
-			 WHERE id = ?`,
-			string(next),
-			now,
-			eligibilityAt,
-			id,
-		)
-		if err != nil {
-			return fmt.Errorf("post-tend transition: update thoughts (rest): %w", err)
+	if decisions.Mark {
+		if _, err := transitionTx(tx, id, core.StateTended, nowTime, transitionParams{note: decisions.Note}); err != nil {
+			return fmt.Errorf("commit tend: mark tended: %w", err)
 		}
-	} else {
-		_, err = tx.Exec(
-			`UPDATE thoughts
-			 SET current_state = ?,
-			     updated_at = ?
-			 WHERE id = ?`,
-			string(next),
-			now,
-			id,
-		)
-		if err != nil {
-			return fmt.Errorf("post-tend transition: update thoughts: %w", err)
+
+		params := transitionParams{}
+		if decisions.Next == core.StateResting {
+			params.rest = decisions.Rest
+			params.spaced = true
+		}
+		if _, err := transitionTx(tx, id, decisions.Next, nowTime, params); err != nil {
+			return fmt.Errorf("commit tend: resolve: %w", err)
 		}
 	}
 
-	_, err = tx.Exec(
-		`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note)
-		 VALUES (?, ?, ?, ?, ?, ?)`,
-		id,
-		"state_change",
-		now,
-		string(prev),
-		string(next),
-		noteValue,
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tend: commit: %w", err)
+	}
+	return nil
+}
+
+// ListPendingTended returns thoughts left in the tended state without a resolution,
+// for example by an interrupted tend in an older version.
+func (s *Store) ListPendingTended() ([]core.Thought, error) {
+	if s == nil {
+		return nil, fmt.Errorf("list pending tended: store is nil")
+	}
+	if s.db == nil {
+		return nil, fmt.Errorf("list pending tended: db is nil")
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, uid, content, tend_counter FROM thoughts WHERE current_state = ? ORDER BY id ASC`,
+		string(core.StateTended),
 	)
 	if err != nil {
-		return fmt.Errorf("post-tend transition: insert event: %w", err)
+		return nil, fmt.Errorf("list pending tended: query: %w", err)
 	}
+	defer rows.Close()
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("post-tend transition: commit: %w", err)
+	thoughts := make([]core.Thought, 0)
+	for rows.Next() {
+		thought := core.Thought{CurrentState: core.StateTended}
+		if err := rows.Scan(&thought.ID, &thought.UID, &thought.Content, &thought.TendCounter); err != nil {
+			return nil, fmt.Errorf("list pending tended: scan: %w", err)
+		}
+		thoughts = append(thoughts, thought)
 	}
-
-	return nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list pending tended: rows: %w", err)
+	}
+	return thoughts, nil
 }
 
+// ToEvolve transitions a captured, resting or tended thought into the evolved state.
 func (s *Store) ToEvolve(id int64) error {
 	if s == nil {
 		return fmt.Errorf("to evolve: store is nil")
@@ -815,7 +1226,7 @@ func (s *Store) ToEvolve(id int64) error {
 		return fmt.Errorf("to evolve: invalid thought ID")
 	}
 
-	tx, err := s.db.Begin()
+	tx, err := s.beginTx()
 	if err != nil {
 		return fmt.Errorf("to evolve: begin tx: %w", err)
 	}
@@ -823,54 +1234,122 @@ func (s *Store) ToEvolve(id int64) error {
 		_ = tx.Rollback()
 	}()
 
-	var prevStateStr string
-	row := tx.QueryRow(`SELECT current_state FROM thoughts WHERE id = ?`, id)
-	if err := row.Scan(&prevStateStr); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("to evolve: not found")
-		}
-		return fmt.Errorf("to evolve: read current_state: %w", err)
+	_, err = transitionTx(tx, id, core.StateEvolved, time.Now(), transitionParams{})
+	if err != nil {
+		return fmt.Errorf("to evolve: %w", err)
 	}
 
-	prev := core.State(prevStateStr)
-	if prev == core.StateEvolved {
-		return fmt.Errorf("to evolve: thought is in terminal state (%s)", prev)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("to evolve: commit: %w", err)
+	}
+	return nil
+}
+
+// RestThought moves a captured, resting or tended thought into resting and appends a state-change event.
+// A nil rest lets core.ResurfacePolicy choose the period from the thought's tend count.
+func (s *Store) RestThought(id int64, rest *core.RestPeriod, note *string) error {
+	if s == nil {
+		return fmt.Errorf("rest thought: store is nil")
+	}
+	if s.db == nil {
+		return fmt.Errorf("rest thought: db is nil")
+	}
+	if id <= 0 {
+		return fmt.Errorf("rest thought: invalid thought ID")
+	}
+	if rest != nil && rest.Until.IsZero() {
+		return fmt.Errorf("rest thought: rest period is zero")
+	}
+
+	tx, err := s.beginTx()
+	if err != nil {
+		return fmt.Errorf("rest thought: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = transitionTx(tx, id, core.StateResting, time.Now(), transitionParams{note: note, rest: rest, spaced: true})
+	if err != nil {
+		return fmt.Errorf("rest thought: %w", err)
 	}
 
-	now := time.Now().UTC().Format(time.RFC3339Nano)
-	state := core.StateEvolved
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("rest thought: commit: %w", err)
+	}
+	return nil
+}
 
-	sqlQuery := `UPDATE thoughts
-		 		 SET current_state = ?,
-		     		updated_at = ?
-		 		 WHERE id = ?`
-	res, err := tx.Exec(sqlQuery, state, now, id)
+// ArchiveThought moves a captured, resting or tended thought into long-term memory and appends a state-change event.
+func (s *Store) ArchiveThought(id int64, note *string) error {
+	if s == nil {
+		return fmt.Errorf("archive thought: store is nil")
+	}
+	if s.db == nil {
+		return fmt.Errorf("archive thought: db is nil")
+	}
+	if id <= 0 {
+		return fmt.Errorf("archive thought: invalid thought ID")
+	}
 
+	tx, err := s.beginTx()
 	if err != nil {
-		return fmt.Errorf("to evolve: update thoughts: %w", err)
+		return fmt.Errorf("archive thought: begin tx: %w", err)
 	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
 
-	rows, err := res.RowsAffected()
+	_, err = transitionTx(tx, id, core.StateArchived, time.Now(), transitionParams{note: note})
 	if err != nil {
-		return fmt.Errorf("to evolve: rows affected: %w", err)
+		return fmt.Errorf("archive thought: %w", err)
 	}
-	if rows == 0 {
-		return fmt.Errorf("to evolve: not found")
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("archive thought: commit: %w", err)
 	}
+	return nil
+}
 
-	_, err = tx.Exec(`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note) VALUES (?, ?, ?, ?, ?, ?)`, id, "state_change", now, string(prev), string(state), nil)
+// ReviveThought brings an evolved, archived or released thought back to resting with a fresh eligibility_at.
+// The revived event keeps the state the thought came back from.
+func (s *Store) ReviveThought(id int64, note *string) error {
+	if s == nil {
+		return fmt.Errorf("revive thought: store is nil")
+	}
+	if s.db == nil {
+		return fmt.Errorf("revive thought: db is nil")
+	}
+	if id <= 0 {
+		return fmt.Errorf("revive thought: invalid thought ID")
+	}
+
+	tx, err := s.beginTx()
 	if err != nil {
-		return fmt.Errorf("to evolve: insert event: %w", err)
+		return fmt.Errorf("revive thought: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	nowTime := time.Now().UTC()
+	rest := core.DefaultRest(nowTime)
+	prev, err := transitionTx(tx, id, core.StateResting, nowTime, transitionParams{note: note, rest: &rest})
+	if err != nil {
+		return fmt.Errorf("revive thought: %w", err)
+	}
+	if !core.IsTerminal(prev) {
+		return fmt.Errorf("revive thought: only evolved, archived or released thoughts can be revived: %w", &core.TransitionError{From: prev, To: core.StateResting})
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("to evolve: commit: %w", err)
+		return fmt.Errorf("revive thought: commit: %w", err)
 	}
 	return nil
 }
 
-// ReleaseThought permanently deletes a thought and its associated events.
-func (s *Store) ReleaseThought(id int64) error {
+// ReleaseThought moves a thought into the released state, keeping it and its history as a tombstone.
+func (s *Store) ReleaseThought(id int64, note *string) error {
 	if s == nil {
 		return fmt.Errorf("release thought: store is nil")
 	}
@@ -881,7 +1360,7 @@ func (s *Store) ReleaseThought(id int64) error {
 		return fmt.Errorf("release thought: invalid thought ID")
 	}
 
-	tx, err := s.db.Begin()
+	tx, err := s.beginTx()
 	if err != nil {
 		return fmt.Errorf("release thought: begin tx: %w", err)
 	}
@@ -889,31 +1368,164 @@ func (s *Store) ReleaseThought(id int64) error {
 		_ = tx.Rollback()
 	}()
 
-	_, err = tx.Exec(`DELETE FROM events WHERE thought_id = ?`, id)
+	_, err = transitionTx(tx, id, core.StateReleased, time.Now(), transitionParams{note: note})
+	if err != nil {
+		return fmt.Errorf("release thought: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("release thought: commit: %w", err)
+	}
+	return nil
+}
+
+// PurgeThought permanently deletes a released thought and its associated events.
+func (s *Store) PurgeThought(id int64) error {
+	if s == nil {
+		return fmt.Errorf("purge thought: store is nil")
+	}
+	if s.db == nil {
+		return fmt.Errorf("purge thought: db is nil")
+	}
+	if id <= 0 {
+		return fmt.Errorf("purge thought: invalid thought ID")
+	}
+
+	tx, err := s.beginTx()
 	if err != nil {
-		return fmt.Errorf("release thought: delete events: %w", err)
+		return fmt.Errorf("purge thought: begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var stateStr string
+	row := tx.QueryRow(`SELECT current_state FROM thoughts WHERE id = ?`, id)
+	if err := row.Scan(&stateStr); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("purge thought: not found")
+		}
+		return fmt.Errorf("purge thought: read current_state: %w", err)
+	}
+	if core.State(stateStr) != core.StateReleased {
+		return fmt.Errorf("purge thought: thought is %s; release it first", stateStr)
 	}
 
-	res, err := tx.Exec(`DELETE FROM thoughts WHERE id = ?`, id)
+	if err := purgeTx(tx, []int64{id}); err != nil {
+		return fmt.Errorf("purge thought: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("purge thought: commit: %w", err)
+	}
+	return nil
+}
+
+// PurgeReleasedBefore permanently deletes every thought released before cutoff and returns how many were removed.
+func (s *Store) PurgeReleasedBefore(cutoff time.Time) (int, error) {
+	if s == nil {
+		return 0, fmt.Errorf("purge released: store is nil")
+	}
+	if s.db == nil {
+		return 0, fmt.Errorf("purge released: db is nil")
+	}
+	if cutoff.IsZero() {
+		return 0, fmt.Errorf("purge released: cutoff is zero")
+	}
+
+	tx, err := s.beginTx()
 	if err != nil {
-		return fmt.Errorf("release thought: delete thought: %w", err)
+		return 0, fmt.Errorf("purge released: begin tx: %w", err)
 	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
 
-	affected, err := res.RowsAffected()
+	// The thought was let go at its latest release event; updated_at can move later, for
+	// example when a snapshot is repaired.
+	rows, err := tx.Query(
+		`SELECT t.id
+		 FROM thoughts t
+		 JOIN events e ON e.thought_id = t.id AND e.kind = ? AND e.next_state = ?
+		 WHERE t.current_state = ?
+		 GROUP BY t.id
+		 HAVING MAX(e.at) < ?
+		 ORDER BY t.id ASC`,
+		core.EventStateChange,
+		string(core.StateReleased),
+		string(core.StateReleased),
+		formatTime(cutoff),
+	)
 	if err != nil {
-		return fmt.Errorf("release thought: rows affected: %w", err)
+		return 0, fmt.Errorf("purge released: query: %w", err)
+	}
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("purge released: scan: %w", err)
+		}
+		ids = append(ids, id)
 	}
-	if affected == 0 {
-		return fmt.Errorf("release thought: not found")
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return 0, fmt.Errorf("purge released: rows: %w", err)
+	}
+	_ = rows.Close()
+
+	if err := purgeTx(tx, ids); err != nil {
+		return 0, fmt.Errorf("purge released: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("release thought: commit: %w", err)
+		return 0, fmt.Errorf("purge released: commit: %w", err)
+	}
+	return len(ids), nil
+}
+
+// purgeTx deletes the given thoughts and their events inside tx, logging each purge.
+// It is the only path allowed to remove events.
+func purgeTx(tx *sql.Tx, ids []int64) error {
+	now := formatTime(time.Now())
+	for _, id := range ids {
+		_, err := tx.Exec(`DELETE FROM revisions WHERE thought_id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("delete revisions: %w", err)
+		}
+
+		_, err = tx.Exec(`DELETE FROM thought_tags WHERE thought_id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("delete tags: %w", err)
+		}
+
+		err = purgeEventsTx(tx, id, now)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("not found (id=%d)", id)
+		}
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(`DELETE FROM thoughts WHERE id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("delete thought: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("rows affected: %w", err)
+		}
+		if affected == 0 {
+			return fmt.Errorf("not found (id=%d)", id)
+		}
 	}
 	return nil
 }
 
 // CountTendReady returns the number of thoughts currently eligible for tending.
+// Outside the configured surfacing windows nothing is ready.
 func (s *Store) CountTendReady() (int, error) {
 	if s == nil {
 		return 0, fmt.Errorf("count tend ready: store is nil")
@@ -922,9 +1534,22 @@ func (s *Store) CountTendReady() (int, error) {
 		return 0, fmt.Errorf("count tend ready: db is nil")
 	}
 
-	nowStr := time.Now().UTC().Format(time.RFC3339Nano)
+	nowTime := time.Now()
+	if !core.Windows.Open(nowTime) {
+		return 0, nil
+	}
+
+	selected, limited, err := s.todaysSelection(nowTime)
+	if err != nil {
+		return 0, fmt.Errorf("count tend ready: %w", err)
+	}
+	if limited {
+		return len(selected), nil
+	}
+
+	nowStr := formatTime(nowTime)
 	var n int
-	err := s.db.QueryRow(
+	err = s.db.QueryRow(
 		`SELECT COUNT(*)
 		 FROM thoughts
 		 WHERE current_state IN (?, ?)
@@ -949,8 +1574,6 @@ func (s *Store) DidCountTendChange(newCount int) bool {
 		return false
 	}
 
-	_ = s.ensureAppStateTable()
-
 	var oldValue string
 	err := s.db.QueryRow(`SELECT value FROM app_state WHERE key = ?`, appStateKeyLastTendReadyCount).Scan(&oldValue)
 	if err != nil {
@@ -975,23 +1598,6 @@ func (s *Store) DidCountTendChange(newCount int) bool {
 	return true
 }
 
-func (s *Store) ensureAppStateTable() error {
-	if s == nil || s.db == nil {
-		return fmt.Errorf("ensure app_state: store/db is nil")
-	}
-	_, err := s.db.Exec(`
-		CREATE TABLE IF NOT EXISTS app_state (
-			key TEXT PRIMARY KEY,
-			value TEXT NOT NULL,
-			updated_at TEXT NOT NULL
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("ensure app_state: %w", err)
-	}
-	return nil
-}
-
 func (s *Store) setAppStateInt(key string, value int) error {
 	if s == nil || s.db == nil {
 		return fmt.Errorf("set app_state: store/db is nil")
@@ -999,7 +1605,7 @@ func (s *Store) setAppStateInt(key string, value int) error {
 	if strings.TrimSpace(key) == "" {
 		return fmt.Errorf("set app_state: empty key")
 	}
-	now := time.Now().UTC().Format(time.RFC3339Nano)
+	now := formatTime(time.Now())
 	_, err := s.db.Exec(
 		`INSERT INTO app_state(key, value, updated_at)
 		 VALUES (?, ?, ?)
@@ -1013,133 +1619,3 @@ func (s *Store) setAppStateInt(key string, value int) error {
 	}
 	return nil
 }
-
-// ReindexThoughtIDs renumbers thought IDs to be contiguous (1..N) and rewrites event foreign keys.
-// This is a UX nicety for a local-only CLI and is intended to be called after deletions.
-func (s *Store) ReindexThoughtIDs() error {
-	if s == nil {
-		return fmt.Errorf("reindex thought ids: store is nil")
-	}
-	if s.db == nil {
-		return fmt.Errorf("reindex thought ids: db is nil")
-	}
-
-	tx, err := s.db.Begin()
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: begin tx: %w", err)
-	}
-	defer func() {
-		_ = tx.Rollback()
-	}()
-
-	// Map old IDs to new contiguous IDs.
-	_, err = tx.Exec(`CREATE TEMP TABLE IF NOT EXISTS thought_id_map (old_id INTEGER PRIMARY KEY, new_id INTEGER NOT NULL);`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: create map: %w", err)
-	}
-	_, err = tx.Exec(`DELETE FROM thought_id_map;`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: clear map: %w", err)
-	}
-	_, err = tx.Exec(`
-		INSERT INTO thought_id_map(old_id, new_id)
-		SELECT id, ROW_NUMBER() OVER (ORDER BY id)
-		FROM thoughts
-		ORDER BY id;
-	`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: populate map: %w", err)
-	}
-
-	// Create new tables.
-	_, err = tx.Exec(`
-		CREATE TABLE thoughts_new (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			content TEXT NOT NULL,
-			current_state TEXT NOT NULL,
-			tend_counter INTEGER NOT NULL DEFAULT 0,
-			created_at TEXT NOT NULL,
-			updated_at TEXT NOT NULL,
-			last_tended_at TEXT NULL,
-			eligibility_at TEXT NOT NULL,
-			valence INTEGER NULL,
-			energy INTEGER NULL
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: create thoughts_new: %w", err)
-	}
-
-	_, err = tx.Exec(`
-		CREATE TABLE events_new (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			thought_id INTEGER NOT NULL,
-			kind TEXT NOT NULL,
-			at TEXT NOT NULL,
-			previous_state TEXT NULL,
-			next_state TEXT NULL,
-			note TEXT NULL,
-			FOREIGN KEY(thought_id) REFERENCES thoughts_new(id)
-		);
-	`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: create events_new: %w", err)
-	}
-
-	// Copy data with remapped IDs.
-	_, err = tx.Exec(`
-		INSERT INTO thoughts_new (id, content, current_state, tend_counter, created_at, updated_at, last_tended_at, eligibility_at, valence, energy)
-		SELECT m.new_id, t.content, t.current_state, t.tend_counter, t.created_at, t.updated_at, t.last_tended_at, t.eligibility_at, t.valence, t.energy
-		FROM thoughts t
-		JOIN thought_id_map m ON m.old_id = t.id
-		ORDER BY m.new_id;
-	`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: copy thoughts: %w", err)
-	}
-
-	_, err = tx.Exec(`
-		INSERT INTO events_new (id, thought_id, kind, at, previous_state, next_state, note)
-		SELECT e.id, m.new_id, e.kind, e.at, e.previous_state, e.next_state, e.note
-		FROM events e
-		JOIN thought_id_map m ON m.old_id = e.thought_id
-		ORDER BY e.id;
-	`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: copy events: %w", err)
-	}
-
-	// Drop old tables and swap in the new ones.
-	_, err = tx.Exec(`DROP TABLE events;`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: drop events: %w", err)
-	}
-	_, err = tx.Exec(`DROP TABLE thoughts;`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: drop thoughts: %w", err)
-	}
-
-	_, err = tx.Exec(`ALTER TABLE thoughts_new RENAME TO thoughts;`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: rename thoughts: %w", err)
-	}
-	_, err = tx.Exec(`ALTER TABLE events_new RENAME TO events;`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: rename events: %w", err)
-	}
-
-	// Recreate indexes.
-	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_thoughts_state_eligibility ON thoughts(current_state, eligibility_at);`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: create idx_thoughts_state_eligibility: %w", err)
-	}
-	_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_events_thought_id_at ON events(thought_id, at);`)
-	if err != nil {
-		return fmt.Errorf("reindex thought ids: create idx_events_thought_id_at: %w", err)
-	}
-
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("reindex thought ids: commit: %w", err)
-	}
-	return nil
-}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// newTestStore opens a freshly migrated garden in a temporary directory.
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	return openStoreAt(t, filepath.Join(t.TempDir(), "peony.db"))
+}
+
+// addRipeThought captures content and makes it ripe an hour ago.
+func addRipeThought(t *testing.T, st *Store, content string) int64 {
+	t.Helper()
+	id, _, err := st.CreateThought(content, core.RestFor(time.Now(), time.Hour), core.Feeling{})
+	if err != nil {
+		t.Fatalf("create thought: %v", err)
+	}
+	ripeAt := formatTime(time.Now().Add(-time.Hour))
+	if _, err := st.db.Exec(`UPDATE thoughts SET eligibility_at = ? WHERE id = ?`, ripeAt, id); err != nil {
+		t.Fatalf("ripen #%d: %v", id, err)
+	}
+	return id
+}
+
+// seedBaselineDB creates a database at path laid out as the first peony release left it, at
+// schema version 2, then runs stmts against it.
+func seedBaselineDB(t *testing.T, path string, stmts ...string) {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=rwc")
+	if err != nil {
+		t.Fatalf("open baseline: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY);`); err != nil {
+		t.Fatalf("create schema_migrations: %v", err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := migrateBaseSchema(tx); err != nil {
+		t.Fatalf("baseline schema: %v", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (2);`); err != nil {
+		t.Fatalf("record baseline version: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit baseline: %v", err)
+	}
+
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed %q: %v", stmt, err)
+		}
+	}
+}
+
+// openStoreAt opens and migrates the database at path.
+func openStoreAt(t *testing.T, path string) *Store {
+	t.Helper()
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	st, err := New(db)
+	if err != nil {
+		t.Fatalf("new store: %v", err)
+	}
+	return st
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/divijg19/peony/internal/core"
+)
+
+// readTags returns a thought's tags in sorted order.
+func readTags(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}, id int64) ([]string, error) {
+	rows, err := q.Query(`SELECT tag FROM thought_tags WHERE thought_id = ? ORDER BY tag ASC`, id)
+	if err != nil {
+		return nil, fmt.Errorf("query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]string, 0)
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("tags rows: %w", err)
+	}
+	return tags, nil
+}
+
+// setTagsTx replaces a thought's tags with tags, which must be normalized and sorted, and records
+// a tagged event describing what changed. Nothing is written when the tags are unchanged.
+func setTagsTx(tx *sql.Tx, id int64, tags []string, at string) error {
+	prev, err := readTags(tx, id)
+	if err != nil {
+		return err
+	}
+	detail := core.DescribeTagChange(prev, tags)
+	if detail == "" {
+		return nil
+	}
+
+	if _, err := tx.Exec(`DELETE FROM thought_tags WHERE thought_id = ?`, id); err != nil {
+		return fmt.Errorf("clear tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT INTO thought_tags (thought_id, tag) VALUES (?, ?)`, id, tag); err != nil {
+			return fmt.Errorf("insert tag %q: %w", tag, err)
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE thoughts SET updated_at = ? WHERE id = ?`, at, id); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO events (thought_id, kind, at, previous_state, next_state, note, eligibility_at, detail)
+		 VALUES (?, ?, ?, NULL, NULL, NULL, NULL, ?)`,
+		id,
+		core.EventTagged,
+		at,
+		detail,
+	)
+	if err != nil {
+		return fmt.Errorf("insert tagged event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package storage
+
+import "time"
+
+// timeLayout is how every timestamp is stored: UTC with a fixed nine-digit fraction. Unlike
+// RFC3339Nano, which drops trailing zeros, every value has the same width, so comparing the text
+// in SQL (eligibility_at <= ?, updated_at < ?) agrees with comparing the times.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// formatTime renders t for storage.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// parseTime reads a stored timestamp. Values written before the fixed width are still accepted.
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// uidAlphabet omits easily confused characters (0/o, 1/l/i) so handles can be read aloud or retyped.
+const uidAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// uidLength is the number of characters in a thought UID.
+const uidLength = 8
+
+// newThoughtUID returns a random, permanent handle for a thought.
+// A UID always contains at least one letter so it can never be mistaken for a display ID.
+func newThoughtUID() (string, error) {
+	buf := make([]byte, uidLength)
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("generate uid: %w", err)
+		}
+
+		hasLetter := false
+		for i, b := range buf {
+			c := uidAlphabet[int(b)%len(uidAlphabet)]
+			buf[i] = c
+			if c >= 'a' && c <= 'z' {
+				hasLetter = true
+			}
+		}
+		if hasLetter {
+			return string(buf), nil
+		}
+	}
+}